@@ -2,6 +2,7 @@ package stragollum
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,18 +11,46 @@ import (
 
 // DataAPICommander is a helper for making HTTP POST requests to the Data API.
 type DataAPICommander struct {
-	url   string
-	token *string
+	url            string
+	token          *string
+	timeoutOptions TimeoutOptions
+	vectorEncoding VectorEncoding
+	numberDecoding NumberDecoding
+	httpClient     *http.Client
+	// tables tells whether the commander addresses a table (see encoder)
+	tables bool
 }
 
 // NewDataAPICommander creates a new DataAPICommander with the given URL and optional token.
 func NewDataAPICommander(url string, token *string) *DataAPICommander {
 	return &DataAPICommander{
-		url:   url,
-		token: token,
+		url:            url,
+		token:          token,
+		vectorEncoding: DefaultVectorEncoding,
+		numberDecoding: DefaultNumberDecoding,
+		httpClient:     &http.Client{},
 	}
 }
 
+// WithTimeoutOptions sets the default timeouts for the requests issued by the commander.
+func (c *DataAPICommander) WithTimeoutOptions(timeoutOptions TimeoutOptions) *DataAPICommander {
+	c.timeoutOptions = timeoutOptions
+	return c
+}
+
+// WithVectorEncoding sets the serialization of DataAPIVector values in the requests.
+func (c *DataAPICommander) WithVectorEncoding(vectorEncoding VectorEncoding) *DataAPICommander {
+	c.vectorEncoding = vectorEncoding
+	return c
+}
+
+// WithNumberDecoding sets the representation of the numbers of the documents read into
+// untyped values.
+func (c *DataAPICommander) WithNumberDecoding(numberDecoding NumberDecoding) *DataAPICommander {
+	c.numberDecoding = numberDecoding
+	return c
+}
+
 // URL returns the commander's URL.
 func (c *DataAPICommander) URL() string {
 	return c.url
@@ -32,12 +61,48 @@ func (c *DataAPICommander) Token() *string {
 	return c.token
 }
 
+// TimeoutOptions returns the commander's default timeouts.
+func (c *DataAPICommander) TimeoutOptions() TimeoutOptions {
+	return c.timeoutOptions
+}
+
+// VectorEncoding returns the serialization of DataAPIVector values in the requests.
+func (c *DataAPICommander) VectorEncoding() VectorEncoding {
+	return c.vectorEncoding
+}
+
+// NumberDecoding returns the representation of the numbers of the documents read into
+// untyped values.
+func (c *DataAPICommander) NumberDecoding() NumberDecoding {
+	return c.numberDecoding
+}
+
+// operationContext derives a context bounded by the commander's operation timeout.
+// The returned cancel function must always be called.
+func (c *DataAPICommander) operationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withOperationTimeout(ctx, c.timeoutOptions.OperationTimeout)
+}
+
 // RawRequest sends a POST request with the given payload to the commander's URL.
 // It sets headers from the provided map, if any.
 // If a token is present in the DataAPICommander, it adds a "Token" header.
 // It returns the response body as bytes and an error if any occurred (including non-2xx HTTP status codes).
 func (ac *DataAPICommander) RawRequest(payload []byte, headers map[string]string) ([]byte, error) {
-	req, err := http.NewRequest("POST", ac.url, bytes.NewReader(payload))
+	return ac.RawRequestContext(context.Background(), payload, headers)
+}
+
+// RawRequestContext is like RawRequest, but the request is bound to the given context.
+// The commander's request timeout, if any, is applied on top of the context;
+// its expiration is reported as a *DataAPITimeoutError.
+func (ac *DataAPICommander) RawRequestContext(ctx context.Context, payload []byte, headers map[string]string) ([]byte, error) {
+	reqCtx := ctx
+	if ac.timeoutOptions.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, ac.timeoutOptions.RequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, "POST", ac.url, bytes.NewReader(payload))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -52,15 +117,20 @@ func (ac *DataAPICommander) RawRequest(payload []byte, headers map[string]string
 		req.Header.Set("Token", *ac.token)
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ac.httpClient.Do(req)
 	if err != nil {
+		if tErr := timeoutError(ctx, reqCtx, ac.timeoutOptions.RequestTimeout, err); tErr != nil {
+			return nil, tErr
+		}
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
+		if tErr := timeoutError(ctx, reqCtx, ac.timeoutOptions.RequestTimeout, err); tErr != nil {
+			return nil, tErr
+		}
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
@@ -73,10 +143,19 @@ func (ac *DataAPICommander) RawRequest(payload []byte, headers map[string]string
 
 // Request sends a JSON request and parses the JSON response.
 // It automatically sets the "Content-Type" and "Accept" headers to "application/json".
-// Input and output are automatically marshalled/unmarshalled as JSON.
+// Input and output are automatically marshalled/unmarshalled as JSON; the numbers of the
+// untyped values of the output are represented according to the commander's NumberDecoding.
+// If the response contains a non-empty top-level "errors" array, a *DataAPIResponseError
+// is returned; responseObj is populated with the rest of the response all the same,
+// so that callers can inspect partial results.
 func (ac *DataAPICommander) Request(requestObj interface{}, responseObj interface{}) error {
+	return ac.RequestContext(context.Background(), requestObj, responseObj)
+}
+
+// RequestContext is like Request, but the underlying HTTP request is bound to the given context.
+func (ac *DataAPICommander) RequestContext(ctx context.Context, requestObj interface{}, responseObj interface{}) error {
 	// Marshal request object to JSON
-	payload, err := json.Marshal(requestObj)
+	payload, err := ac.encoder().marshalPayload(requestObj)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request to JSON: %w", err)
 	}
@@ -91,15 +170,103 @@ func (ac *DataAPICommander) Request(requestObj interface{}, responseObj interfac
 	}
 
 	// Send raw request
-	respBody, err := ac.RawRequest(payload, headers)
+	respBody, err := ac.RawRequestContext(ctx, payload, headers)
 	if err != nil {
 		return err
 	}
 
+	// Detect API errors first: they take precedence over a response with an unexpected shape
+	if err := checkResponseErrors(requestObj, respBody); err != nil {
+		// Best effort: expose the non-error part of the response (e.g. partial results)
+		_ = ac.unmarshalResponse(respBody, responseObj)
+		return err
+	}
+
 	// Unmarshal JSON response
-	if err := json.Unmarshal(respBody, responseObj); err != nil {
+	if err := ac.unmarshalResponse(respBody, responseObj); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
+
+// unmarshalResponse decodes a response body into responseObj, with the numbers of untyped
+// values represented according to the commander's NumberDecoding.
+func (ac *DataAPICommander) unmarshalResponse(respBody []byte, responseObj interface{}) error {
+	if err := unmarshalNumbers(respBody, responseObj, ac.numberDecoding); err != nil {
+		return err
+	}
+	switch target := responseObj.(type) {
+	case *map[string]interface{}:
+		convertNumbers(*target, ac.numberDecoding)
+	case *interface{}:
+		*target = convertNumbers(*target, ac.numberDecoding)
+	}
+	return nil
+}
+
+// encoder returns an encoder with the serialization settings of the commander.
+func (ac *DataAPICommander) encoder() *encoder {
+	return &encoder{vectorEncoding: ac.vectorEncoding, tables: ac.tables}
+}
+
+// checkResponseErrors looks for a top-level "errors" array in a response body
+// and turns it into a *DataAPIResponseError.
+func checkResponseErrors(requestObj interface{}, respBody []byte) error {
+	var envelope struct {
+		Errors []DataAPIErrorDescriptor `json:"errors"`
+	}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	if len(envelope.Errors) == 0 {
+		return nil
+	}
+
+	var rawResponse map[string]any
+	if err := json.Unmarshal(respBody, &rawResponse); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &DataAPIResponseError{
+		Errors:      envelope.Errors,
+		Command:     requestObj,
+		RawResponse: rawResponse,
+	}
+}
 
+// decodeDocument decodes a document found in a response into v.
+// A missing or null document leaves v untouched.
+func (ac *DataAPICommander) decodeDocument(raw json.RawMessage, v interface{}) error {
+	if isNullDocument(raw) {
+		return nil
+	}
+	if err := unmarshalDocument(raw, v, ac.numberDecoding); err != nil {
+		return fmt.Errorf("failed to decode document: %w", err)
+	}
+	return nil
+}
+
+// isNullDocument tells whether a document found in a response is missing or null.
+func isNullDocument(raw json.RawMessage) bool {
+	return len(raw) == 0 || string(raw) == "null"
+}
+
+// requestOk sends a command whose response is expected to be {"status": {"ok": 1}}.
+func (ac *DataAPICommander) requestOk(ctx context.Context, payload interface{}) error {
+	var response struct {
+		Status struct {
+			Ok *int `json:"ok"`
+		} `json:"status"`
+	}
+
+	err := ac.RequestContext(ctx, payload, &response)
+	if err != nil {
+		return err
+	}
+
+	// Defensive: check for missing status or ok fields
+	if response.Status.Ok == nil || *response.Status.Ok != 1 {
+		return fmt.Errorf("unexpected response: expected status.ok == 1, got: %+v", response)
+	}
 	return nil
 }
@@ -0,0 +1,550 @@
+package stragollum
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Request payloads are marshalled with encoding/json, after a preparation pass
+// (see encoder.prepare) converting the few values the Data API serializes in its
+// own way: times, vectors and big floats, the extended JSON types of this
+// package, and the fields of document structs mapped onto reserved fields with
+// the "dataapi" struct tag (see DataAPITag).
+
+// DataAPITag is the struct tag mapping a field of a document struct onto one of
+// the reserved document fields, regardless of its json name:
+//
+//	type Item struct {
+//		Key       string    `json:"key" dataapi:"id"`               // stored as "_id"
+//		Embedding []float32 `json:"embedding" dataapi:"vector"`     // stored as "$vector"
+//		Text      string    `json:"text" dataapi:"vectorize"`       // stored as "$vectorize"
+//	}
+const DataAPITag = "dataapi"
+
+// reservedFieldNames maps the values of the DataAPITag to the reserved document fields.
+var reservedFieldNames = map[string]string{
+	"id":        IDField,
+	"vector":    VectorField,
+	"vectorize": VectorizeField,
+}
+
+// orderedField is a key-value pair of an orderedObject.
+type orderedField struct {
+	key   string
+	value any
+}
+
+// orderedObject is a JSON object which marshals its fields in the given order.
+type orderedObject []orderedField
+
+// MarshalJSON implements json.Marshaler.
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// structField describes how a struct field is encoded.
+type structField struct {
+	name      string
+	jsonName  string
+	index     []int
+	omitEmpty bool
+	tagged    bool
+}
+
+// structFieldsCache caches the result of structFields, by type.
+var structFieldsCache sync.Map
+
+// structFields returns the encodable fields of a struct type, following the rules of encoding/json
+// for names and embedded structs; the reserved fields set with the DataAPITag are renamed.
+func structFields(t reflect.Type) []structField {
+	if cached, ok := structFieldsCache.Load(t); ok {
+		return cached.([]structField)
+	}
+
+	type candidate struct {
+		field structField
+		depth int
+	}
+	var candidates []candidate
+	var walk func(t reflect.Type, index []int, depth int, visited map[reflect.Type]bool)
+	walk = func(t reflect.Type, index []int, depth int, visited map[reflect.Type]bool) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		defer delete(visited, t)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			fieldIndex := append(append([]int{}, index...), i)
+
+			if f.Anonymous && name == "" {
+				ft := f.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(ft, fieldIndex, depth+1, visited)
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+
+			sf := structField{
+				name:      name,
+				index:     fieldIndex,
+				tagged:    name != "",
+				omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+			}
+			if sf.name == "" {
+				sf.name = f.Name
+			}
+			sf.jsonName = sf.name
+			if reserved, ok := reservedFieldNames[f.Tag.Get(DataAPITag)]; ok {
+				sf.name = reserved
+				sf.tagged = true
+			}
+			candidates = append(candidates, candidate{field: sf, depth: depth})
+		}
+	}
+	walk(t, nil, 0, map[reflect.Type]bool{})
+
+	// Resolve name conflicts: shallowest wins, then tagged wins, otherwise the field is dropped
+	byName := map[string][]candidate{}
+	var order []string
+	for _, c := range candidates {
+		if _, seen := byName[c.field.name]; !seen {
+			order = append(order, c.field.name)
+		}
+		byName[c.field.name] = append(byName[c.field.name], c)
+	}
+	var fields []structField
+	for _, name := range order {
+		group := byName[name]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].depth < group[j].depth })
+		var dominant []candidate
+		for _, c := range group {
+			if c.depth == group[0].depth {
+				dominant = append(dominant, c)
+			}
+		}
+		if len(dominant) > 1 {
+			var tagged []candidate
+			for _, c := range dominant {
+				if c.field.tagged {
+					tagged = append(tagged, c)
+				}
+			}
+			dominant = tagged
+		}
+		if len(dominant) == 1 {
+			fields = append(fields, dominant[0].field)
+		}
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+
+	structFieldsCache.Store(t, fields)
+	return fields
+}
+
+// lessIndex orders field index sequences as they appear in the struct definition.
+func lessIndex(a, b []int) bool {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// reservedFieldRenames returns, for a struct type, the mapping from reserved document fields
+// (set with the DataAPITag) to the json names of the corresponding fields.
+func reservedFieldRenames(t reflect.Type) map[string]string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var renames map[string]string
+	for _, f := range structFields(t) {
+		if f.name != f.jsonName {
+			if renames == nil {
+				renames = map[string]string{}
+			}
+			renames[f.name] = f.jsonName
+		}
+	}
+	return renames
+}
+
+// treeEncoder is implemented by the types of this package which encode themselves into
+// JSON-ready trees, subject to the serialization settings of the request (see Sort).
+type treeEncoder interface {
+	encodeTree(e *encoder) (any, error)
+}
+
+var (
+	treeEncoderType     = reflect.TypeOf((*treeEncoder)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encoder prepares the values to be marshalled with json.Marshal, according to the
+// serialization settings in effect for the request.
+type encoder struct {
+	vectorEncoding VectorEncoding
+	// tables makes values be serialized as tables expect them: dates as RFC 3339 strings,
+	// UUIDs as plain strings and byte slices as {"$binary": ...} wrappers
+	tables bool
+}
+
+// preparingKey is the key of preparingCache.
+type preparingKey struct {
+	t      reflect.Type
+	tables bool
+}
+
+// preparingCache caches the result of encoder.needsPreparing, by type and settings.
+var preparingCache sync.Map
+
+// needsPreparing tells whether values of type t may hold values which encoding/json does
+// not serialize as the Data API expects: times, vectors, big floats, the types of this
+// package (see treeEncoder), fields mapped onto reserved document fields (see DataAPITag)
+// and, for tables, byte slices. Interfaces may hold anything.
+func (e *encoder) needsPreparing(t reflect.Type) bool {
+	key := preparingKey{t: t, tables: e.tables}
+	if cached, ok := preparingCache.Load(key); ok {
+		return cached.(bool)
+	}
+	needed := e.typeNeedsPreparing(t, map[reflect.Type]bool{})
+	preparingCache.Store(key, needed)
+	return needed
+}
+
+// typeNeedsPreparing implements needsPreparing, visiting each type once.
+func (e *encoder) typeNeedsPreparing(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	if t.Kind() == reflect.Ptr {
+		return e.typeNeedsPreparing(t.Elem(), visited)
+	}
+	if t == timeType || t == dataAPIVectorType || t == bigFloatType || t.Implements(treeEncoderType) {
+		return true
+	}
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return e.tables
+		}
+		return e.typeNeedsPreparing(t.Elem(), visited)
+	case reflect.Array, reflect.Map:
+		return e.typeNeedsPreparing(t.Elem(), visited)
+	case reflect.Struct:
+		for _, f := range structFields(t) {
+			if f.name != f.jsonName || e.typeNeedsPreparing(t.FieldByIndex(f.index).Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// encodeValue converts a Go value into a value marshalling as the Data API expects (see
+// prepare).
+func (e *encoder) encodeValue(v any) (any, error) {
+	return e.prepare(reflect.ValueOf(v))
+}
+
+// prepare returns the value to be marshalled in place of v: the values needing it (see
+// needsPreparing) are converted, and the maps, slices and structs holding them rebuilt.
+// Anything else is left to json.Marshal.
+func (e *encoder) prepare(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+	if v.Kind() == reflect.Interface {
+		return e.prepare(v.Elem())
+	}
+
+	value := v.Interface()
+	switch x := value.(type) {
+	case time.Time:
+		return e.encodeTime(x), nil
+	case DataAPIVector:
+		return e.encodeVector(x), nil
+	case big.Float:
+		return encodeBigFloat(&x)
+	case treeEncoder:
+		return x.encodeTree(e)
+	}
+	if !e.needsPreparing(v.Type()) {
+		return value, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		return e.prepare(v.Elem())
+	case reflect.Map:
+		// Maps with other keys are left to json.Marshal
+		if v.Type().Key().Kind() != reflect.String {
+			return value, nil
+		}
+		object := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			item, err := e.prepare(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			object[iter.Key().String()] = item
+		}
+		return object, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			// Only for tables: encoding/json produces a base64 string otherwise
+			return map[string]any{"$binary": base64.StdEncoding.EncodeToString(v.Bytes())}, nil
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			item, err := e.prepare(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	case reflect.Struct:
+		return e.prepareStruct(v)
+	}
+	return value, nil
+}
+
+// prepareStruct prepares a struct (see prepare). json.Marshal encodes the fields needing
+// no preparation, following its own rules (names, omitempty, the ",string" option...);
+// the others are prepared one by one, and the fields mapped onto reserved document
+// fields renamed. Field order is preserved.
+func (e *encoder) prepareStruct(v reflect.Value) (any, error) {
+	fields := structFields(v.Type())
+
+	// A copy without the values to be prepared, so that they are not encoded twice
+	plain := reflect.New(v.Type()).Elem()
+	plain.Set(v)
+	prepared := make([]bool, len(fields))
+	for i, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		// Fields promoted from unexported embedded structs cannot be read: left to json.Marshal
+		if !ok || !fv.CanInterface() || !e.needsPreparing(fv.Type()) {
+			continue
+		}
+		prepared[i] = true
+		zeroField(plain, f.index)
+	}
+
+	raw, err := json.Marshal(plain.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var encoded map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, err
+	}
+
+	object := make(orderedObject, 0, len(fields))
+	for i, f := range fields {
+		if !prepared[i] {
+			if value, ok := encoded[f.jsonName]; ok {
+				object = append(object, orderedField{key: f.name, value: value})
+			}
+			continue
+		}
+		fv, _ := fieldByIndex(v, f.index)
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		value, err := e.prepare(fv)
+		if err != nil {
+			return nil, err
+		}
+		object = append(object, orderedField{key: f.name, value: value})
+	}
+	return object, nil
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but reports false on nil embedded pointers.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// zeroField sets a field of a struct copy to its zero value, unless it is reached through
+// an embedded pointer (shared with the original struct) or cannot be set.
+func zeroField(v reflect.Value, index []int) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			return
+		}
+		v = v.Field(x)
+	}
+	if v.CanSet() {
+		v.Set(reflect.Zero(v.Type()))
+	}
+}
+
+// isEmptyValue reports whether a value is "empty" in the sense of the omitempty json tag
+// option, for the kinds of the fields needing preparation (scalars never do).
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// marshalPayload encodes a request payload into JSON bytes.
+func (e *encoder) marshalPayload(v any) ([]byte, error) {
+	prepared, err := e.encodeValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(prepared)
+}
+
+// encodeObject encodes v into the fields of a JSON object, in order; it reports false if
+// v is not encoded as an object (null included).
+func (e *encoder) encodeObject(v any) (orderedObject, bool, error) {
+	payload, err := e.marshalPayload(v)
+	if err != nil {
+		return nil, false, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, false, err
+	}
+	object := orderedObject{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false, err
+		}
+		object = append(object, orderedField{key: token.(string), value: value})
+	}
+	return object, true, nil
+}
+
+// unmarshalDocument decodes a JSON document into v. Untyped targets get the extended JSON
+// wrappers converted into Go values (see decodeExtendedValue) and the numbers represented
+// according to numbers; for other targets, dates are made decodable into time.Time fields and,
+// if v points to a struct with fields mapped onto reserved document fields (see
+// DataAPITag), those are renamed first.
+func unmarshalDocument(raw []byte, v any, numbers NumberDecoding) error {
+	switch target := v.(type) {
+	case *map[string]interface{}:
+		var document map[string]interface{}
+		if err := unmarshalNumbers(raw, &document, numbers); err != nil {
+			return err
+		}
+		decodeExtendedValue(document)
+		convertNumbers(document, numbers)
+		*target = document
+		return nil
+	case *interface{}:
+		var document interface{}
+		if err := unmarshalNumbers(raw, &document, numbers); err != nil {
+			return err
+		}
+		*target = convertNumbers(decodeExtendedValue(document), numbers)
+		return nil
+	}
+
+	if bytes.Contains(raw, dateMarker) {
+		normalized, err := normalizeDates(raw, reflect.TypeOf(v))
+		if err != nil {
+			return err
+		}
+		raw = normalized
+	}
+	renames := reservedFieldRenames(reflect.TypeOf(v))
+	if len(renames) == 0 {
+		return unmarshalNumbers(raw, v, numbers)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return unmarshalNumbers(raw, v, numbers)
+	}
+	for reserved, jsonName := range renames {
+		if value, ok := fields[reserved]; ok {
+			delete(fields, reserved)
+			fields[jsonName] = value
+		}
+	}
+	renamed, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return unmarshalNumbers(renamed, v, numbers)
+}
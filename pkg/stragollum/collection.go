@@ -1,6 +1,12 @@
 package stragollum
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
 
 // Collection represents a connection to a specific collection in the database.
 type Collection struct {
@@ -9,6 +15,14 @@ type Collection struct {
 	token       *string
 	keyspace    string
 	commander   *DataAPICommander
+	// database is the Database the Collection was obtained from
+	database *Database
+	// idGeneration is the type of the IDs generated locally for the inserted documents, if any
+	idGeneration string
+	// idGenerationFromDefinition tells that idGeneration is to be read from the definition
+	// of the collection (see WithIDGeneration), guarded by idGenerationMu
+	idGenerationFromDefinition bool
+	idGenerationMu             sync.Mutex
 }
 
 // Keyspace returns the keyspace associated with the Database.
@@ -16,10 +30,165 @@ func (co *Collection) Name() string {
 	return co.name
 }
 
+// Options retrieves the definition of the collection from the Data API.
+// It returns an error if the collection does not exist.
+func (co *Collection) Options() (*CollectionDefinition, error) {
+	return co.OptionsContext(context.Background())
+}
+
+// OptionsContext is like Options, with a context bounding the operation.
+func (co *Collection) OptionsContext(ctx context.Context) (*CollectionDefinition, error) {
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	descriptors, err := co.database.ListCollectionsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, descriptor := range descriptors {
+		if descriptor.Name == co.name {
+			definition := descriptor.Definition
+			return &definition, nil
+		}
+	}
+	return nil, fmt.Errorf("collection %q not found in keyspace %q", co.name, co.keyspace)
+}
+
+// TimeoutOptions returns the Collection's default timeouts.
+func (co *Collection) TimeoutOptions() TimeoutOptions {
+	return co.commander.TimeoutOptions()
+}
+
+// WithTimeoutOptions sets the Collection's default timeouts.
+func (co *Collection) WithTimeoutOptions(timeoutOptions TimeoutOptions) *Collection {
+	co.commander.WithTimeoutOptions(timeoutOptions)
+	return co
+}
+
+// VectorEncoding returns the Collection's serialization of DataAPIVector values.
+func (co *Collection) VectorEncoding() VectorEncoding {
+	return co.commander.VectorEncoding()
+}
+
+// WithVectorEncoding sets the Collection's serialization of DataAPIVector values in the requests.
+func (co *Collection) WithVectorEncoding(vectorEncoding VectorEncoding) *Collection {
+	co.commander.WithVectorEncoding(vectorEncoding)
+	return co
+}
+
+// NumberDecoding returns the Collection's representation of the numbers of the documents
+// read into untyped values.
+func (co *Collection) NumberDecoding() NumberDecoding {
+	return co.commander.NumberDecoding()
+}
+
+// WithNumberDecoding sets the Collection's representation of the numbers of the documents
+// read into untyped values (see NumberDecoding).
+func (co *Collection) WithNumberDecoding(numberDecoding NumberDecoding) *Collection {
+	co.commander.WithNumberDecoding(numberDecoding)
+	return co
+}
+
+// IDGeneration returns the type of the IDs generated locally for the inserted documents,
+// or "" if the IDs are left to the Data API (or the type is yet to be read from the
+// definition of the collection, see WithIDGeneration).
+func (co *Collection) IDGeneration() string {
+	co.idGenerationMu.Lock()
+	defer co.idGenerationMu.Unlock()
+	return co.idGeneration
+}
+
+// WithIDGeneration makes the Collection generate locally the _id of the documents inserted
+// without one, instead of leaving that to the Data API. The IDs are of the defaultId type
+// of the collection, read from its definition at the first insertion (if the collection
+// has no defaultId, the IDs are left to the Data API). An idType, one of the DefaultIDType
+// constants, can be given instead; "" disables the generation.
+func (co *Collection) WithIDGeneration(idType ...string) *Collection {
+	co.idGenerationMu.Lock()
+	defer co.idGenerationMu.Unlock()
+	co.idGeneration = ""
+	co.idGenerationFromDefinition = len(idType) == 0
+	if len(idType) > 0 {
+		co.idGeneration = idType[0]
+	}
+	return co
+}
+
+// idGenerationType returns the type of the IDs to generate for the inserted documents ("" for
+// none), reading the defaultId type of the collection if needed (see WithIDGeneration).
+func (co *Collection) idGenerationType(ctx context.Context) (string, error) {
+	co.idGenerationMu.Lock()
+	defer co.idGenerationMu.Unlock()
+	if co.idGenerationFromDefinition {
+		definition, err := co.OptionsContext(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the defaultId type of the collection: %w", err)
+		}
+		if definition.DefaultID != nil {
+			co.idGeneration = definition.DefaultID.Type
+		}
+		co.idGenerationFromDefinition = false
+	}
+	return co.idGeneration, nil
+}
+
+// withGeneratedID returns the document to be sent in place of document, with a generated
+// _id of type idType (if not "") if document lacks one. A document given an _id is returned
+// as the json.RawMessage of the document sent.
+func (co *Collection) withGeneratedID(idType string, document interface{}) (interface{}, error) {
+	if idType == "" {
+		return document, nil
+	}
+	e := co.commander.encoder()
+	object, ok, err := e.encodeObject(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	if !ok {
+		// Not a document: left for the Data API to reject
+		return document, nil
+	}
+	for _, field := range object {
+		if field.key == IDField {
+			return document, nil
+		}
+	}
+	id, err := generateDocumentID(idType)
+	if err != nil {
+		return nil, err
+	}
+	encodedID, err := e.encodeValue(id)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(append(orderedObject{{key: IDField, value: encodedID}}, object...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	return json.RawMessage(encoded), nil
+}
+
 // InsertOne inserts a single document into the collection.
 // It takes any Go type that can be marshalled to JSON as the document.
-// Returns the inserted document's ID as a string and an error if the operation failed.
-func (co *Collection) InsertOne(document interface{}) (string, error) {
+// Returns the inserted document's ID and an error if the operation failed.
+func (co *Collection) InsertOne(document interface{}) (DocumentID, error) {
+	return co.InsertOneContext(context.Background(), document)
+}
+
+// InsertOneContext is like InsertOne, with a context bounding the operation.
+func (co *Collection) InsertOneContext(ctx context.Context, document interface{}) (DocumentID, error) {
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	idType, err := co.idGenerationType(ctx)
+	if err != nil {
+		return DocumentID{}, err
+	}
+	document, err = co.withGeneratedID(idType, document)
+	if err != nil {
+		return DocumentID{}, err
+	}
+
 	// Create the request payload as per API requirements
 	// The payload structure should be: {"insertOne": {"document": <the input doc>}}
 	requestPayload := struct {
@@ -38,53 +207,352 @@ func (co *Collection) InsertOne(document interface{}) (string, error) {
 	// The API returns: {"status": {"insertedIds": [<the id>]}}
 	var response struct {
 		Status struct {
-			InsertedIds []string `json:"insertedIds"`
+			InsertedIds []DocumentID `json:"insertedIds"`
 		} `json:"status"`
 	}
 
 	// Send the request and parse the response
-	err := co.commander.Request(requestPayload, &response)
+	err = co.commander.RequestContext(ctx, requestPayload, &response)
 	if err != nil {
-		return "", err
+		return DocumentID{}, err
 	}
 
 	// Validate the response
 	if len(response.Status.InsertedIds) == 0 {
-		return "", fmt.Errorf("no document ID returned after insertion")
+		return DocumentID{}, fmt.Errorf("no document ID returned after insertion")
 	}
 
 	// Return the inserted document ID
 	return response.Status.InsertedIds[0], nil
 }
 
+// InsertMany inserts the given documents into the collection, splitting them into chunks
+// sent as separate requests (see InsertManyOptions; options may be nil).
+// It returns the IDs of the inserted documents, in input order.
+// If some documents could not be inserted, the returned error is an *InsertManyError
+// detailing what was inserted and what failed.
+func (co *Collection) InsertMany(documents []interface{}, options *InsertManyOptions) ([]DocumentID, error) {
+	return co.InsertManyContext(context.Background(), documents, options)
+}
+
+// InsertManyContext is like InsertMany, with a context bounding the whole operation.
+func (co *Collection) InsertManyContext(ctx context.Context, documents []interface{}, options *InsertManyOptions) ([]DocumentID, error) {
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	// The documents actually sent, possibly with generated IDs
+	idType, err := co.idGenerationType(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payloads := documents
+	if idType != "" {
+		payloads = make([]interface{}, len(documents))
+		for i, document := range documents {
+			payload, err := co.withGeneratedID(idType, document)
+			if err != nil {
+				return nil, err
+			}
+			payloads[i] = payload
+		}
+	}
+
+	insertedIDs, failures := runInsertMany(ctx, payloads, options, co.insertManyChunk)
+	if len(failures) > 0 {
+		return insertedIDs, &InsertManyError{InsertedIDs: insertedIDs, Failures: failures}
+	}
+	return insertedIDs, nil
+}
+
+// insertManyChunk is a slice of the documents passed to InsertMany, as sent (see
+// Collection.WithIDGeneration).
+type insertManyChunk struct {
+	offset    int
+	documents []interface{}
+}
+
+// failures reports all the documents of the chunk as failed with err.
+func (chunk insertManyChunk) failures(err error) []InsertManyFailure {
+	failures := make([]InsertManyFailure, len(chunk.documents))
+	for i, document := range chunk.documents {
+		failures[i] = InsertManyFailure{Index: chunk.offset + i, Document: document, Err: err}
+	}
+	return failures
+}
+
+// insertManyChunkResult is the outcome of inserting a chunk, with the IDs (of type K) of
+// the inserted documents.
+type insertManyChunkResult[K any] struct {
+	insertedIDs []K
+	failures    []InsertManyFailure
+}
+
+// runInsertMany splits documents into chunks inserted with insertChunk,
+// sequentially or concurrently as set by options (which may be nil). It returns the IDs
+// of the inserted documents and the failures, both in input order.
+func runInsertMany[K any](
+	ctx context.Context,
+	documents []interface{},
+	options *InsertManyOptions,
+	insertChunk func(ctx context.Context, chunk insertManyChunk, ordered bool) insertManyChunkResult[K],
+) ([]K, []InsertManyFailure) {
+	if options == nil {
+		options = &InsertManyOptions{}
+	}
+	chunkSize := options.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultInsertManyChunkSize
+	}
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultInsertManyConcurrency
+	}
+
+	// Split the input into chunks, each remembering its offset in the input
+	var chunks []insertManyChunk
+	for start := 0; start < len(documents); start += chunkSize {
+		end := start + chunkSize
+		if end > len(documents) {
+			end = len(documents)
+		}
+		chunks = append(chunks, insertManyChunk{offset: start, documents: documents[start:end]})
+	}
+
+	results := make([]insertManyChunkResult[K], len(chunks))
+	if options.Ordered {
+		for i, chunk := range chunks {
+			results[i] = insertChunk(ctx, chunk, true)
+			if len(results[i].failures) > 0 {
+				break
+			}
+		}
+	} else {
+		var wg sync.WaitGroup
+		semaphore := make(chan struct{}, concurrency)
+		for i, chunk := range chunks {
+			semaphore <- struct{}{}
+			if err := ctx.Err(); err != nil {
+				// Not launched once the context is done: the whole chunk fails
+				<-semaphore
+				results[i] = insertManyChunkResult[K]{failures: chunk.failures(err)}
+				continue
+			}
+			wg.Add(1)
+			go func(i int, chunk insertManyChunk) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+				results[i] = insertChunk(ctx, chunk, false)
+			}(i, chunk)
+		}
+		wg.Wait()
+	}
+
+	// Reassemble the outcome in input order
+	insertedIDs := make([]K, 0, len(documents))
+	var failures []InsertManyFailure
+	for _, result := range results {
+		insertedIDs = append(insertedIDs, result.insertedIDs...)
+		failures = append(failures, result.failures...)
+	}
+	return insertedIDs, failures
+}
+
+// insertManyDocumentError returns the cause of the failure of a document not inserted by an
+// insertMany command: the Data API error at errorsIdx in apiErr if any, else apiErr itself
+// or, lacking it, the document status.
+func insertManyDocumentError(apiErr *DataAPIResponseError, errorsIdx *int, status string) error {
+	if apiErr != nil && errorsIdx != nil && *errorsIdx < len(apiErr.Errors) {
+		return &DataAPIResponseError{
+			Errors:      []DataAPIErrorDescriptor{apiErr.Errors[*errorsIdx]},
+			Command:     apiErr.Command,
+			RawResponse: apiErr.RawResponse,
+		}
+	}
+	if apiErr != nil {
+		return apiErr
+	}
+	return fmt.Errorf("document not inserted (status %q)", status)
+}
+
+// insertManyChunk inserts a single chunk of documents with one insertMany command.
+func (co *Collection) insertManyChunk(ctx context.Context, chunk insertManyChunk, ordered bool) insertManyChunkResult[DocumentID] {
+	requestPayload := struct {
+		InsertMany struct {
+			Documents []interface{} `json:"documents"`
+			Options   struct {
+				Ordered                 bool `json:"ordered"`
+				ReturnDocumentResponses bool `json:"returnDocumentResponses"`
+			} `json:"options"`
+		} `json:"insertMany"`
+	}{}
+	requestPayload.InsertMany.Documents = chunk.documents
+	requestPayload.InsertMany.Options.Ordered = ordered
+	requestPayload.InsertMany.Options.ReturnDocumentResponses = true
+
+	// The API returns one entry per input document, in order:
+	// {"status": {"documentResponses": [{"_id": <id>, "status": "OK|ERROR|SKIPPED", "errorsIdx": <n>}]}}
+	var response struct {
+		Status struct {
+			DocumentResponses []struct {
+				ID        DocumentID `json:"_id"`
+				Status    string     `json:"status"`
+				ErrorsIdx *int       `json:"errorsIdx"`
+			} `json:"documentResponses"`
+		} `json:"status"`
+	}
+
+	var result insertManyChunkResult[DocumentID]
+	err := co.commander.RequestContext(ctx, requestPayload, &response)
+
+	var apiErr *DataAPIResponseError
+	if err == nil && len(response.Status.DocumentResponses) != len(chunk.documents) {
+		err = fmt.Errorf("unexpected response: expected %d document responses, got %d",
+			len(chunk.documents), len(response.Status.DocumentResponses))
+	}
+	if err != nil && (!errors.As(err, &apiErr) || len(response.Status.DocumentResponses) != len(chunk.documents)) {
+		// The whole chunk failed (or the outcome of single documents is unknown)
+		result.failures = chunk.failures(err)
+		return result
+	}
+
+	for i, documentResponse := range response.Status.DocumentResponses {
+		switch documentResponse.Status {
+		case "OK":
+			result.insertedIDs = append(result.insertedIDs, documentResponse.ID)
+		case "SKIPPED":
+			// Not attempted after a failure in an ordered insertion
+		default:
+			err := insertManyDocumentError(apiErr, documentResponse.ErrorsIdx, documentResponse.Status)
+			result.failures = append(result.failures, InsertManyFailure{Index: chunk.offset + i, Document: chunk.documents[i], Err: err})
+		}
+	}
+	return result
+}
+
 // FindOne runs a search and returns a document, or nil if not found.
 // The filter parameter is a JSON object that specifies the search criteria.
-func (co *Collection) FindOne(filter interface{}) (map[string]interface{}, error) {
+// Sort, projection and the other find options can optionally be passed.
+func (co *Collection) FindOne(filter interface{}, options ...*FindOneOptions) (map[string]interface{}, error) {
+	return co.FindOneContext(context.Background(), filter, options...)
+}
+
+// FindOneContext is like FindOne, with a context bounding the operation.
+func (co *Collection) FindOneContext(ctx context.Context, filter interface{}, options ...*FindOneOptions) (map[string]interface{}, error) {
+	var document map[string]interface{}
+	if _, err := co.findOne(ctx, filter, &document, options...); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// findOne runs a findOne command, decoding the document found (if any) into document.
+// It reports whether a document was found.
+func (co *Collection) findOne(ctx context.Context, filter interface{}, document interface{}, options ...*FindOneOptions) (bool, error) {
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
 	// Create the request payload as per API requirements
+	command := findCommand{Filter: normalizeFilter(filter)}
+	if len(options) > 0 && options[0] != nil {
+		opts := options[0]
+		if err := validateSort(opts.Sort); err != nil {
+			return false, err
+		}
+		command.Sort = opts.Sort
+		command.Projection = opts.Projection
+		if opts.IncludeSimilarity || opts.IncludeSortVector {
+			command.Options = &findCommandOptions{
+				IncludeSimilarity: opts.IncludeSimilarity,
+				IncludeSortVector: opts.IncludeSortVector,
+			}
+		}
+	}
 	requestPayload := struct {
-		FindOne struct {
-			Filter interface{} `json:"filter"`
-		} `json:"findOne"`
+		FindOne findCommand `json:"findOne"`
 	}{
-		FindOne: struct {
-			Filter interface{} `json:"filter"`
-		}{
-			Filter: filter,
-		},
+		FindOne: command,
 	}
 
 	// Define the expected response structure
 	var response struct {
 		Data struct {
-			Document map[string]interface{} `json:"document"`
+			Document json.RawMessage `json:"document"`
 		} `json:"data"`
 	}
 
 	// Send the request and parse the response
-	err := co.commander.Request(requestPayload, &response)
+	err := co.commander.RequestContext(ctx, requestPayload, &response)
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+
+	if isNullDocument(response.Data.Document) {
+		return false, nil
+	}
+	return true, co.commander.decodeDocument(response.Data.Document, document)
+}
+
+// Find runs a search and returns a cursor over the matching documents.
+// The filter parameter is a JSON object that specifies the search criteria;
+// options may be nil. No request is issued until the cursor is first used:
+// the cursor then follows the API pagination transparently.
+func (co *Collection) Find(filter interface{}, options *FindOptions) *FindCursor {
+	return co.FindContext(context.Background(), filter, options)
+}
+
+// FindContext is like Find. The given context bounds every request issued by the cursor.
+func (co *Collection) FindContext(ctx context.Context, filter interface{}, options *FindOptions) *FindCursor {
+	if options == nil {
+		options = &FindOptions{}
+	}
+	if err := validateSort(options.Sort); err != nil {
+		cursor := newFindCursor(ctx, co.commander, nil, options.Limit)
+		cursor.err = err
+		return cursor
+	}
+	command := findCommand{
+		Filter:     normalizeFilter(filter),
+		Sort:       options.Sort,
+		Projection: options.Projection,
+	}
+	commandOptions := findCommandOptions{
+		Limit:             options.Limit,
+		Skip:              options.Skip,
+		IncludeSimilarity: options.IncludeSimilarity,
+		IncludeSortVector: options.IncludeSortVector,
+	}
+
+	fetch := func(ctx context.Context, pageState *string) (*cursorPage, error) {
+		pageOptions := commandOptions
+		pageOptions.PageState = pageState
+		pageCommand := command
+		if pageOptions != (findCommandOptions{}) {
+			pageCommand.Options = &pageOptions
+		}
+		requestPayload := struct {
+			Find findCommand `json:"find"`
+		}{
+			Find: pageCommand,
+		}
+
+		var response struct {
+			Data struct {
+				Documents     []json.RawMessage `json:"documents"`
+				NextPageState *string           `json:"nextPageState"`
+			} `json:"data"`
+			Status struct {
+				SortVector DataAPIVector `json:"sortVector"`
+			} `json:"status"`
+		}
+		if err := co.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+			return nil, err
+		}
+		return &cursorPage{
+			documents:     response.Data.Documents,
+			nextPageState: response.Data.NextPageState,
+			sortVector:    response.Status.SortVector,
+		}, nil
 	}
 
-	return response.Data.Document, nil
+	return newFindCursor(ctx, co.commander, fetch, options.Limit)
 }
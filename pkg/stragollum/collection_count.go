@@ -0,0 +1,76 @@
+package stragollum
+
+import (
+	"context"
+	"fmt"
+)
+
+// CountDocuments returns the exact number of documents matching the filter (nil to count all).
+// The count is capped: if it exceeds upperBound, or the API stops counting at its own
+// limit, a *TooManyDocumentsToCountError is returned.
+func (co *Collection) CountDocuments(filter interface{}, upperBound int) (int, error) {
+	return co.CountDocumentsContext(context.Background(), filter, upperBound)
+}
+
+// CountDocumentsContext is like CountDocuments, with a context bounding the operation.
+func (co *Collection) CountDocumentsContext(ctx context.Context, filter interface{}, upperBound int) (int, error) {
+	if upperBound < 0 {
+		return 0, fmt.Errorf("invalid upper bound for counting: %d", upperBound)
+	}
+
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	requestPayload := map[string]any{
+		"countDocuments": map[string]any{
+			"filter": normalizeFilter(filter),
+		},
+	}
+
+	var response struct {
+		Status struct {
+			Count    int  `json:"count"`
+			MoreData bool `json:"moreData"`
+		} `json:"status"`
+	}
+	if err := co.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+		return 0, err
+	}
+
+	if response.Status.MoreData {
+		if response.Status.Count > upperBound {
+			return 0, &TooManyDocumentsToCountError{Limit: upperBound}
+		}
+		return 0, &TooManyDocumentsToCountError{Limit: response.Status.Count, HitServerLimit: true}
+	}
+	if response.Status.Count > upperBound {
+		return 0, &TooManyDocumentsToCountError{Limit: upperBound}
+	}
+	return response.Status.Count, nil
+}
+
+// EstimatedDocumentCount returns a fast, approximate count of all documents in the collection,
+// based on the storage statistics.
+func (co *Collection) EstimatedDocumentCount() (int, error) {
+	return co.EstimatedDocumentCountContext(context.Background())
+}
+
+// EstimatedDocumentCountContext is like EstimatedDocumentCount, with a context bounding the operation.
+func (co *Collection) EstimatedDocumentCountContext(ctx context.Context) (int, error) {
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	requestPayload := map[string]any{
+		"estimatedDocumentCount": map[string]any{},
+	}
+
+	var response struct {
+		Status struct {
+			Count int `json:"count"`
+		} `json:"status"`
+	}
+	if err := co.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+		return 0, err
+	}
+	return response.Status.Count, nil
+}
@@ -1,6 +1,10 @@
 package stragollum
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
 
 // CollectionDefinition represents the configuration for creating a collection.
 type CollectionDefinition struct {
@@ -16,8 +20,55 @@ type CollectionDefaultIDOptions struct {
 }
 
 type CollectionLexicalOptions struct {
-	Analyzer string `json:"analyzer,omitempty"`
-	Enabled  *bool  `json:"enabled,omitempty"`
+	// Analyzer is the name of the analyzer (e.g. "standard").
+	Analyzer string `json:"-"`
+	// AnalyzerConfig is an analyzer configuration, sent instead of Analyzer if not nil.
+	// Definitions read from the Data API set it when the analyzer is not given by name.
+	AnalyzerConfig map[string]any `json:"-"`
+	Enabled        *bool          `json:"enabled,omitempty"`
+}
+
+// lexicalOptionsJSON is the JSON form of CollectionLexicalOptions, with the analyzer
+// given either by name or as a configuration.
+type lexicalOptionsJSON struct {
+	Analyzer any   `json:"analyzer,omitempty"`
+	Enabled  *bool `json:"enabled,omitempty"`
+}
+
+// analyzer returns the analyzer as sent to the Data API (nil if none).
+func (o *CollectionLexicalOptions) analyzer() any {
+	if o.AnalyzerConfig != nil {
+		return o.AnalyzerConfig
+	}
+	if o.Analyzer != "" {
+		return o.Analyzer
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o CollectionLexicalOptions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(lexicalOptionsJSON{Analyzer: o.analyzer(), Enabled: o.Enabled})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the analyzer either by name or as a
+// configuration.
+func (o *CollectionLexicalOptions) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Analyzer json.RawMessage `json:"analyzer"`
+		Enabled  *bool           `json:"enabled"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*o = CollectionLexicalOptions{Enabled: raw.Enabled}
+	if len(raw.Analyzer) == 0 || string(raw.Analyzer) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw.Analyzer, &o.Analyzer); err == nil {
+		return nil
+	}
+	return json.Unmarshal(raw.Analyzer, &o.AnalyzerConfig)
 }
 
 type CollectionRerankOptions struct {
@@ -138,3 +189,111 @@ func (cd *CollectionDefinition) Validate() error {
 	}
 	return nil
 }
+
+// differences lists the settings of the (requested) definition which differ from an
+// existing one. Settings left unspecified in the requested definition, for which the
+// Data API applies defaults, are not compared; the presence of the vector and indexing
+// settings is, as it changes the nature of the collection.
+func (cd CollectionDefinition) differences(existing CollectionDefinition) []CollectionDefinitionDifference {
+	var differences []CollectionDefinitionDifference
+	compare := func(field string, requested any, actual any) {
+		if !sameSetting(requested, actual) {
+			differences = append(differences, CollectionDefinitionDifference{Field: field, Requested: requested, Existing: actual})
+		}
+	}
+
+	if cd.DefaultID != nil {
+		actual := ""
+		if existing.DefaultID != nil {
+			actual = existing.DefaultID.Type
+		}
+		compare("defaultId.type", cd.DefaultID.Type, actual)
+	}
+
+	if cd.Vector == nil || existing.Vector == nil {
+		if cd.Vector != nil || existing.Vector != nil {
+			compare("vector", optionalValue(cd.Vector), optionalValue(existing.Vector))
+		}
+	} else {
+		if cd.Vector.Dimension != nil {
+			compare("vector.dimension", *cd.Vector.Dimension, optionalValue(existing.Vector.Dimension))
+		}
+		if cd.Vector.Metric != "" {
+			compare("vector.metric", cd.Vector.Metric, existing.Vector.Metric)
+		}
+		if cd.Vector.SourceModel != "" {
+			compare("vector.sourceModel", cd.Vector.SourceModel, existing.Vector.SourceModel)
+		}
+		if cd.Vector.Service != nil {
+			var provider, modelName string
+			if existing.Vector.Service != nil {
+				provider, modelName = existing.Vector.Service.Provider, existing.Vector.Service.ModelName
+			}
+			compare("vector.service.provider", cd.Vector.Service.Provider, provider)
+			compare("vector.service.modelName", cd.Vector.Service.ModelName, modelName)
+		}
+	}
+
+	if len(cd.Indexing) > 0 || len(existing.Indexing) > 0 {
+		compare("indexing", cd.Indexing, existing.Indexing)
+	}
+
+	if cd.Lexical != nil {
+		actual := existing.Lexical
+		if actual == nil {
+			actual = &CollectionLexicalOptions{}
+		}
+		if cd.Lexical.Enabled != nil {
+			compare("lexical.enabled", *cd.Lexical.Enabled, optionalValue(actual.Enabled))
+		}
+		if analyzer := cd.Lexical.analyzer(); analyzer != nil {
+			compare("lexical.analyzer", analyzer, actual.analyzer())
+		}
+	}
+
+	if cd.Rerank != nil {
+		actual := existing.Rerank
+		if actual == nil {
+			actual = &CollectionRerankOptions{}
+		}
+		if cd.Rerank.Enabled != nil {
+			compare("rerank.enabled", *cd.Rerank.Enabled, optionalValue(actual.Enabled))
+		}
+		if cd.Rerank.Service != nil {
+			var provider, modelName string
+			if actual.Service != nil {
+				provider, modelName = actual.Service.Provider, actual.Service.ModelName
+			}
+			compare("rerank.service.provider", cd.Rerank.Service.Provider, provider)
+			compare("rerank.service.modelName", cd.Rerank.Service.ModelName, modelName)
+		}
+	}
+
+	return differences
+}
+
+// optionalValue returns the value of an optional setting, or nil if it is absent.
+func optionalValue[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// sameSetting tells whether two settings have the same JSON representation.
+func sameSetting(a any, b any) bool {
+	return reflect.DeepEqual(jsonTree(a), jsonTree(b))
+}
+
+// jsonTree converts a value into its untyped JSON representation (nil if not encodable).
+func jsonTree(value any) any {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var tree any
+	if err := json.Unmarshal(encoded, &tree); err != nil {
+		return nil
+	}
+	return tree
+}
@@ -0,0 +1,144 @@
+package stragollum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyFilter is returned by Collection.DeleteMany when called with an empty filter:
+// use Collection.DeleteAll to explicitly delete every document in the collection.
+var ErrEmptyFilter = errors.New("empty filter: use DeleteAll to delete all documents in the collection")
+
+// DeleteResult reports the outcome of a delete operation.
+type DeleteResult struct {
+	DeletedCount int
+}
+
+// deleteCommand is the body of the deleteOne/deleteMany commands.
+type deleteCommand struct {
+	Filter any `json:"filter"`
+	Sort   any `json:"sort,omitempty"`
+}
+
+// deleteStatus is the "status" object returned by the delete family of commands.
+type deleteStatus struct {
+	DeletedCount int  `json:"deletedCount"`
+	MoreData     bool `json:"moreData"`
+}
+
+// isEmptyFilter tells whether a filter would match all documents, judging from what the
+// Data API receives: nil, empty maps, empty structs and json.RawMessage("{}") all are.
+func (e *encoder) isEmptyFilter(filter any) (bool, error) {
+	payload, err := e.marshalPayload(filter)
+	if err != nil {
+		return false, fmt.Errorf("invalid filter: %w", err)
+	}
+	// null decodes into an empty map as well
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		// Not an object: the Data API will reject it
+		return false, nil
+	}
+	return len(fields) == 0, nil
+}
+
+// DeleteOne deletes a single document matching the filter.
+// If several documents match, the sort option (e.g. a vector sort) decides which one is deleted.
+// options may be nil.
+func (co *Collection) DeleteOne(filter interface{}, options *DeleteOneOptions) (*DeleteResult, error) {
+	return co.DeleteOneContext(context.Background(), filter, options)
+}
+
+// DeleteOneContext is like DeleteOne, with a context bounding the operation.
+func (co *Collection) DeleteOneContext(ctx context.Context, filter interface{}, options *DeleteOneOptions) (*DeleteResult, error) {
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	command := deleteCommand{Filter: normalizeFilter(filter)}
+	if options != nil {
+		if err := validateSort(options.Sort); err != nil {
+			return nil, err
+		}
+		command.Sort = options.Sort
+	}
+	requestPayload := struct {
+		DeleteOne deleteCommand `json:"deleteOne"`
+	}{
+		DeleteOne: command,
+	}
+
+	var response struct {
+		Status deleteStatus `json:"status"`
+	}
+	if err := co.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+		return nil, err
+	}
+	return &DeleteResult{DeletedCount: response.Status.DeletedCount}, nil
+}
+
+// DeleteMany deletes all documents matching the filter. As the API deletes the matches in
+// batches, the command is repeated until no more matches are reported: the returned
+// count is the total. An empty filter is rejected with ErrEmptyFilter (see DeleteAll).
+func (co *Collection) DeleteMany(filter interface{}) (*DeleteResult, error) {
+	return co.DeleteManyContext(context.Background(), filter)
+}
+
+// DeleteManyContext is like DeleteMany, with a context bounding the whole operation.
+func (co *Collection) DeleteManyContext(ctx context.Context, filter interface{}) (*DeleteResult, error) {
+	empty, err := co.commander.encoder().isEmptyFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if empty {
+		return nil, ErrEmptyFilter
+	}
+
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	requestPayload := struct {
+		DeleteMany deleteCommand `json:"deleteMany"`
+	}{
+		DeleteMany: deleteCommand{Filter: filter},
+	}
+
+	result := &DeleteResult{}
+	for {
+		var response struct {
+			Status deleteStatus `json:"status"`
+		}
+		if err := co.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+			return result, err
+		}
+		result.DeletedCount += response.Status.DeletedCount
+
+		if !response.Status.MoreData {
+			return result, nil
+		}
+	}
+}
+
+// DeleteAll deletes all documents in the collection (truncation), in a single request.
+// The API does not report how many documents were deleted.
+func (co *Collection) DeleteAll() error {
+	return co.DeleteAllContext(context.Background())
+}
+
+// DeleteAllContext is like DeleteAll, with a context bounding the operation.
+func (co *Collection) DeleteAllContext(ctx context.Context) error {
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	requestPayload := struct {
+		DeleteMany deleteCommand `json:"deleteMany"`
+	}{
+		DeleteMany: deleteCommand{Filter: map[string]any{}},
+	}
+
+	var response struct {
+		Status deleteStatus `json:"status"`
+	}
+	return co.commander.RequestContext(ctx, requestPayload, &response)
+}
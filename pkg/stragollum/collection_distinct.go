@@ -0,0 +1,164 @@
+package stragollum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseDistinctKey splits a dotted key (e.g. "metadata.tags.0") into its path segments,
+// also returning the (safe) part of the key usable in a projection: the segments before
+// the first numeric one, which could denote an array index (documents being objects,
+// a leading numeric segment can only be a field name).
+func parseDistinctKey(key string) ([]string, string, error) {
+	if key == "" {
+		return nil, "", fmt.Errorf("distinct: empty key")
+	}
+	path := strings.Split(key, ".")
+	projectionPath := path
+	for i, segment := range path {
+		if segment == "" {
+			return nil, "", fmt.Errorf("distinct: invalid key %q", key)
+		}
+		if _, isIndex := arrayIndex(segment); isIndex && i > 0 && len(projectionPath) == len(path) {
+			projectionPath = path[:i]
+		}
+	}
+	return path, strings.Join(projectionPath, "."), nil
+}
+
+// arrayIndex interprets a path segment as an array index, if made of digits only.
+func arrayIndex(segment string) (int, bool) {
+	for _, r := range segment {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(segment)
+	return index, err == nil
+}
+
+// extractDistinctValues collects the values found at path in value, calling emit for each:
+// objects are walked by field name, arrays either by index (numeric segments) or by
+// applying the rest of the path to each of their elements (nested arrays excepted). Arrays
+// found at the end of the path are flattened (one level only), as a match on any of their
+// elements would be.
+func extractDistinctValues(path []string, value interface{}, emit func(interface{})) {
+	if len(path) == 0 {
+		if items, ok := value.([]interface{}); ok {
+			for _, item := range items {
+				emit(item)
+			}
+			return
+		}
+		emit(value)
+		return
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if child, ok := v[path[0]]; ok {
+			extractDistinctValues(path[1:], child, emit)
+		}
+	case []interface{}:
+		if index, isIndex := arrayIndex(path[0]); isIndex {
+			if index < len(v) {
+				extractDistinctValues(path[1:], v[index], emit)
+			}
+			return
+		}
+		// Only one array level is unwound: nested arrays are not traversed
+		for _, item := range v {
+			if _, nested := item.([]interface{}); !nested {
+				extractDistinctValues(path, item, emit)
+			}
+		}
+	}
+}
+
+// distinctKey returns a canonical representation of a value for deduplication: values of
+// different JSON types never collide (e.g. 1 and "1"), numbers compare by value (1 and 1.0), dates by instant,
+// and objects compare by content regardless of the order of their fields.
+func distinctKey(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return "b:" + strconv.FormatBool(v), nil
+	case string:
+		return "s:" + v, nil
+	case time.Time:
+		return "d:" + strconv.FormatInt(v.UnixMilli(), 10), nil
+	}
+	// Numbers compare by their exact value, whatever their representation (see NumberDecoding)
+	if rat := numberRat(value); rat != nil {
+		return "n:" + rat.RatString(), nil
+	}
+	// encoding/json sorts map keys, making the encoding of objects canonical
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("distinct: cannot compare value %v: %w", value, err)
+	}
+	return "j:" + string(encoded), nil
+}
+
+// Distinct returns the distinct values of the given key among the documents matching
+// the filter (nil for all documents), in order of first occurrence. The Data API has no
+// such command: the matching documents are read with a find, projected on the key, and
+// the values are collected client-side (consider the amount of documents involved).
+//
+// The key is a dotted path (e.g. "metadata.tenant") which can contain numeric segments,
+// indexing into arrays (e.g. "tags.0"). Arrays are traversed: "items.sku" collects the
+// sku of every element of items, and an array found at the key contributes each of its
+// elements rather than the array itself. Only one array level is unwound: [[1, 2], [3]]
+// contributes [1, 2] and [3].
+func (co *Collection) Distinct(key string, filter interface{}) ([]interface{}, error) {
+	return co.DistinctContext(context.Background(), key, filter)
+}
+
+// DistinctContext is like Distinct, with a context bounding the whole operation.
+func (co *Collection) DistinctContext(ctx context.Context, key string, filter interface{}) ([]interface{}, error) {
+	path, projectionKey, err := parseDistinctKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	cursor := co.FindContext(ctx, filter, &FindOptions{Projection: map[string]any{projectionKey: true}})
+	defer cursor.Close()
+
+	values := []interface{}{}
+	seen := map[string]bool{}
+	var extractErr error
+	for cursor.Next() {
+		document, err := cursor.Document()
+		if err != nil {
+			return nil, err
+		}
+		extractDistinctValues(path, document, func(value interface{}) {
+			if extractErr != nil {
+				return
+			}
+			k, err := distinctKey(value)
+			if err != nil {
+				extractErr = err
+				return
+			}
+			if !seen[k] {
+				seen[k] = true
+				values = append(values, value)
+			}
+		})
+		if extractErr != nil {
+			return nil, extractErr
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,191 @@
+package stragollum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// findAndModifyCommandOptions is the "options" object of the findOneAndUpdate/findOneAndReplace commands.
+type findAndModifyCommandOptions struct {
+	ReturnDocument ReturnDocument `json:"returnDocument,omitempty"`
+	Upsert         bool           `json:"upsert,omitempty"`
+}
+
+// findAndModifyCommand is the body of the findOneAndUpdate/findOneAndReplace/findOneAndDelete commands.
+type findAndModifyCommand struct {
+	Filter      any                          `json:"filter"`
+	Update      any                          `json:"update,omitempty"`
+	Replacement any                          `json:"replacement,omitempty"`
+	Sort        any                          `json:"sort,omitempty"`
+	Projection  any                          `json:"projection,omitempty"`
+	Options     *findAndModifyCommandOptions `json:"options,omitempty"`
+}
+
+// newFindAndModifyCommandOptions returns the command options, or nil if there are none to send.
+func newFindAndModifyCommandOptions(returnDocument ReturnDocument, upsert bool) *findAndModifyCommandOptions {
+	if returnDocument == "" && !upsert {
+		return nil
+	}
+	return &findAndModifyCommandOptions{ReturnDocument: returnDocument, Upsert: upsert}
+}
+
+// findAndModify runs one of the find-and-modify commands, decoding the returned document
+// (if any) into document and the status into status. It reports whether a document was returned.
+func (co *Collection) findAndModify(ctx context.Context, commandName string, command findAndModifyCommand, document interface{}, status interface{}) (bool, error) {
+	if err := validateSort(command.Sort); err != nil {
+		return false, err
+	}
+
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	requestPayload := map[string]findAndModifyCommand{commandName: command}
+
+	var response struct {
+		Data struct {
+			Document json.RawMessage `json:"document"`
+		} `json:"data"`
+		Status json.RawMessage `json:"status"`
+	}
+	if err := co.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+		return false, err
+	}
+	if len(response.Status) > 0 {
+		if err := json.Unmarshal(response.Status, status); err != nil {
+			return false, fmt.Errorf("failed to unmarshal response status: %w", err)
+		}
+	}
+	if document == nil || isNullDocument(response.Data.Document) {
+		return false, nil
+	}
+	return true, co.commander.decodeDocument(response.Data.Document, document)
+}
+
+// findOneAndUpdateCommand builds the findOneAndUpdate command.
+func findOneAndUpdateCommand(filter interface{}, update interface{}, options *FindOneAndUpdateOptions) findAndModifyCommand {
+	if options == nil {
+		options = &FindOneAndUpdateOptions{}
+	}
+	return findAndModifyCommand{
+		Filter:     normalizeFilter(filter),
+		Update:     update,
+		Sort:       options.Sort,
+		Projection: options.Projection,
+		Options:    newFindAndModifyCommandOptions(options.ReturnDocument, options.Upsert),
+	}
+}
+
+// findOneAndReplaceCommand builds the findOneAndReplace command.
+func findOneAndReplaceCommand(filter interface{}, replacement interface{}, options *FindOneAndReplaceOptions) findAndModifyCommand {
+	if options == nil {
+		options = &FindOneAndReplaceOptions{}
+	}
+	return findAndModifyCommand{
+		Filter:      normalizeFilter(filter),
+		Replacement: replacement,
+		Sort:        options.Sort,
+		Projection:  options.Projection,
+		Options:     newFindAndModifyCommandOptions(options.ReturnDocument, options.Upsert),
+	}
+}
+
+// findOneAndDeleteCommand builds the findOneAndDelete command.
+func findOneAndDeleteCommand(filter interface{}, options *FindOneAndDeleteOptions) findAndModifyCommand {
+	if options == nil {
+		options = &FindOneAndDeleteOptions{}
+	}
+	return findAndModifyCommand{
+		Filter:     normalizeFilter(filter),
+		Sort:       options.Sort,
+		Projection: options.Projection,
+	}
+}
+
+// FindOneAndUpdate atomically updates a document matching the filter and returns it
+// (or nil if none matched), together with the update status.
+// The document returned is the one before the update unless ReturnDocumentAfter is requested.
+// options may be nil.
+func (co *Collection) FindOneAndUpdate(filter interface{}, update interface{}, options *FindOneAndUpdateOptions) (map[string]interface{}, *UpdateResult, error) {
+	return co.FindOneAndUpdateContext(context.Background(), filter, update, options)
+}
+
+// FindOneAndUpdateContext is like FindOneAndUpdate, with a context bounding the operation.
+func (co *Collection) FindOneAndUpdateContext(ctx context.Context, filter interface{}, update interface{}, options *FindOneAndUpdateOptions) (map[string]interface{}, *UpdateResult, error) {
+	var document map[string]interface{}
+	var status updateStatus
+	command := findOneAndUpdateCommand(filter, update, options)
+	if _, err := co.findAndModify(ctx, "findOneAndUpdate", command, &document, &status); err != nil {
+		return nil, nil, err
+	}
+	result := &UpdateResult{}
+	status.addTo(result)
+	return document, result, nil
+}
+
+// FindOneAndReplace atomically replaces a document matching the filter and returns it
+// (or nil if none matched), together with the replacement status.
+// The document returned is the one before the replacement unless ReturnDocumentAfter is requested.
+// options may be nil.
+func (co *Collection) FindOneAndReplace(filter interface{}, replacement interface{}, options *FindOneAndReplaceOptions) (map[string]interface{}, *UpdateResult, error) {
+	return co.FindOneAndReplaceContext(context.Background(), filter, replacement, options)
+}
+
+// FindOneAndReplaceContext is like FindOneAndReplace, with a context bounding the operation.
+func (co *Collection) FindOneAndReplaceContext(ctx context.Context, filter interface{}, replacement interface{}, options *FindOneAndReplaceOptions) (map[string]interface{}, *UpdateResult, error) {
+	var document map[string]interface{}
+	var status updateStatus
+	command := findOneAndReplaceCommand(filter, replacement, options)
+	if _, err := co.findAndModify(ctx, "findOneAndReplace", command, &document, &status); err != nil {
+		return nil, nil, err
+	}
+	result := &UpdateResult{}
+	status.addTo(result)
+	return document, result, nil
+}
+
+// FindOneAndDelete atomically deletes a document matching the filter and returns it
+// (or nil if none matched), together with the deletion status. options may be nil.
+func (co *Collection) FindOneAndDelete(filter interface{}, options *FindOneAndDeleteOptions) (map[string]interface{}, *DeleteResult, error) {
+	return co.FindOneAndDeleteContext(context.Background(), filter, options)
+}
+
+// FindOneAndDeleteContext is like FindOneAndDelete, with a context bounding the operation.
+func (co *Collection) FindOneAndDeleteContext(ctx context.Context, filter interface{}, options *FindOneAndDeleteOptions) (map[string]interface{}, *DeleteResult, error) {
+	var document map[string]interface{}
+	var status deleteStatus
+	command := findOneAndDeleteCommand(filter, options)
+	if _, err := co.findAndModify(ctx, "findOneAndDelete", command, &document, &status); err != nil {
+		return nil, nil, err
+	}
+	return document, &DeleteResult{DeletedCount: status.DeletedCount}, nil
+}
+
+// ReplaceOne replaces a single document matching the filter, returning the replacement status.
+// options may be nil.
+func (co *Collection) ReplaceOne(filter interface{}, replacement interface{}, options *ReplaceOneOptions) (*UpdateResult, error) {
+	return co.ReplaceOneContext(context.Background(), filter, replacement, options)
+}
+
+// ReplaceOneContext is like ReplaceOne, with a context bounding the operation.
+func (co *Collection) ReplaceOneContext(ctx context.Context, filter interface{}, replacement interface{}, options *ReplaceOneOptions) (*UpdateResult, error) {
+	if options == nil {
+		options = &ReplaceOneOptions{}
+	}
+	// The API has no replaceOne command: findOneAndReplace is used, returning as little as possible
+	command := findAndModifyCommand{
+		Filter:      normalizeFilter(filter),
+		Replacement: replacement,
+		Sort:        options.Sort,
+		Projection:  map[string]any{"_id": 1},
+		Options:     newFindAndModifyCommandOptions("", options.Upsert),
+	}
+
+	var status updateStatus
+	if _, err := co.findAndModify(ctx, "findOneAndReplace", command, nil, &status); err != nil {
+		return nil, err
+	}
+	result := &UpdateResult{}
+	status.addTo(result)
+	return result, nil
+}
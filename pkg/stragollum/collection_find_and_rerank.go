@@ -0,0 +1,248 @@
+package stragollum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Fields of the hybrid search sort and scores.
+const (
+	// HybridField is the sort field of a hybrid search.
+	HybridField = "$hybrid"
+	// LexicalField holds the text indexed for lexical search, and is the sort field of a lexical search.
+	LexicalField = "$lexical"
+	// RerankScore is the key of the reranker score among the scores of a reranked result.
+	RerankScore = "$rerank"
+)
+
+// HybridSort returns the sort specification of a hybrid search with the same text used for both
+// the vector (through the embedding service) and the lexical search.
+func HybridSort(text string) map[string]any {
+	return map[string]any{HybridField: text}
+}
+
+// HybridVectorizeSort returns the sort specification of a hybrid search with separate texts for
+// the vector (through the embedding service) and the lexical search.
+func HybridVectorizeSort(vectorize string, lexical string) map[string]any {
+	return map[string]any{HybridField: map[string]any{VectorizeField: vectorize, LexicalField: lexical}}
+}
+
+// HybridVectorSort returns the sort specification of a hybrid search by the given vector and lexical text.
+func HybridVectorSort(vector []float32, lexical string) map[string]any {
+	return map[string]any{HybridField: map[string]any{VectorField: DataAPIVector(vector), LexicalField: lexical}}
+}
+
+// FindAndRerankOptions holds the parameters of Collection.FindAndRerank.
+type FindAndRerankOptions struct {
+	// Sort is the hybrid sort, as built with HybridSort, HybridVectorizeSort or HybridVectorSort.
+	Sort       any
+	Projection any
+	// Limit is the number of results returned after reranking.
+	Limit int
+	// HybridLimits is the number of candidates retrieved by each search before reranking:
+	// either a number (for all searches) or a map from the search field ("$vector",
+	// "$lexical") to a number.
+	HybridLimits any
+	// RerankOn is the document field passed to the reranker. It is required unless the
+	// hybrid sort uses $vectorize, whose text is then used.
+	RerankOn string
+	// RerankQuery is the query passed to the reranker, required when the hybrid sort does not
+	// provide it (i.e. with a $vector rather than a text).
+	RerankQuery string
+	// IncludeScores makes each result carry the scores of the individual searches
+	// and of the reranker.
+	IncludeScores     bool
+	IncludeSortVector bool
+}
+
+// findAndRerankCommandOptions is the "options" object of the findAndRerank command.
+type findAndRerankCommandOptions struct {
+	Limit             int    `json:"limit,omitempty"`
+	HybridLimits      any    `json:"hybridLimits,omitempty"`
+	RerankOn          string `json:"rerankOn,omitempty"`
+	RerankQuery       string `json:"rerankQuery,omitempty"`
+	IncludeScores     bool   `json:"includeScores,omitempty"`
+	IncludeSortVector bool   `json:"includeSortVector,omitempty"`
+}
+
+// findAndRerankCommand is the body of the findAndRerank command.
+type findAndRerankCommand struct {
+	Filter     any                          `json:"filter"`
+	Sort       any                          `json:"sort,omitempty"`
+	Projection any                          `json:"projection,omitempty"`
+	Options    *findAndRerankCommandOptions `json:"options,omitempty"`
+}
+
+// RerankedResult is a document returned by a hybrid search, with its scores (if requested
+// with IncludeScores) keyed by source: "$rerank" (see RerankScore), "$vector", "$lexical".
+type RerankedResult[T any] struct {
+	Document T
+	Scores   map[string]float64
+}
+
+// RerankCursor iterates over the results of a hybrid search, in order of relevance.
+// The results are fetched in a single request, issued when the cursor is first used.
+type RerankCursor[T any] struct {
+	ctx       context.Context
+	commander *DataAPICommander
+	payload   interface{}
+
+	documents  []json.RawMessage
+	scores     []map[string]float64
+	next       int // index of the next result
+	current    int // index of the current result, -1 if none
+	started    bool
+	closed     bool
+	err        error
+	sortVector []float32
+}
+
+// fetch issues the findAndRerank command, buffering its results.
+func (c *RerankCursor[T]) fetch() error {
+	ctx, cancel := c.commander.operationContext(c.ctx)
+	defer cancel()
+
+	var response struct {
+		Data struct {
+			Documents []json.RawMessage `json:"documents"`
+		} `json:"data"`
+		Status struct {
+			DocumentResponses []struct {
+				Scores map[string]float64 `json:"scores"`
+			} `json:"documentResponses"`
+			SortVector DataAPIVector `json:"sortVector"`
+		} `json:"status"`
+	}
+	c.started = true
+	if err := c.commander.RequestContext(ctx, c.payload, &response); err != nil {
+		return err
+	}
+	c.documents = response.Data.Documents
+	c.scores = make([]map[string]float64, len(c.documents))
+	for i, documentResponse := range response.Status.DocumentResponses {
+		if i < len(c.scores) {
+			c.scores[i] = documentResponse.Scores
+		}
+	}
+	c.sortVector = response.Status.SortVector
+	return nil
+}
+
+// start fetches the results, if not done yet. It reports false if the cursor is unusable.
+func (c *RerankCursor[T]) start() bool {
+	if c.closed || c.err != nil {
+		return false
+	}
+	if !c.started {
+		if err := c.fetch(); err != nil {
+			c.err = err
+			return false
+		}
+	}
+	return true
+}
+
+// Next advances the cursor to the next result, fetching the results on first use.
+// It returns false when the results are exhausted or an error occurred (check Err).
+func (c *RerankCursor[T]) Next() bool {
+	c.current = -1
+	if !c.start() || c.next >= len(c.documents) {
+		return false
+	}
+	c.current = c.next
+	c.next++
+	return true
+}
+
+// Current decodes and returns the current result.
+func (c *RerankCursor[T]) Current() (*RerankedResult[T], error) {
+	if c.closed {
+		return nil, ErrCursorClosed
+	}
+	if c.current < 0 {
+		return nil, ErrNoCurrentDocument
+	}
+	result := &RerankedResult[T]{Scores: c.scores[c.current]}
+	if err := c.commander.decodeDocument(c.documents[c.current], &result.Document); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// All decodes and returns all the remaining results, then closes the cursor.
+func (c *RerankCursor[T]) All() ([]RerankedResult[T], error) {
+	defer c.Close()
+	results := []RerankedResult[T]{}
+	for c.Next() {
+		result, err := c.Current()
+		if err != nil {
+			return results, err
+		}
+		results = append(results, *result)
+	}
+	return results, c.Err()
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (c *RerankCursor[T]) Err() error {
+	return c.err
+}
+
+// Close closes the cursor, discarding the remaining results. It always returns nil.
+func (c *RerankCursor[T]) Close() error {
+	c.closed = true
+	c.documents = nil
+	c.scores = nil
+	c.current = -1
+	return nil
+}
+
+// SortVector returns the vector used for the hybrid search, as returned by the API when the
+// IncludeSortVector option is set (nil otherwise). The results are fetched if needed.
+func (c *RerankCursor[T]) SortVector() ([]float32, error) {
+	if c.closed {
+		return nil, ErrCursorClosed
+	}
+	c.start()
+	return c.sortVector, c.err
+}
+
+// findAndRerank prepares a cursor running the findAndRerank command, with results decoded as T.
+func findAndRerank[T any](ctx context.Context, co *Collection, filter interface{}, options *FindAndRerankOptions) *RerankCursor[T] {
+	cursor := &RerankCursor[T]{ctx: ctx, commander: co.commander, current: -1}
+	if options == nil || options.Sort == nil {
+		cursor.err = fmt.Errorf("findAndRerank: a hybrid sort is required")
+		return cursor
+	}
+	command := findAndRerankCommand{
+		Filter:     normalizeFilter(filter),
+		Sort:       options.Sort,
+		Projection: options.Projection,
+	}
+	commandOptions := findAndRerankCommandOptions{
+		Limit:             options.Limit,
+		HybridLimits:      options.HybridLimits,
+		RerankOn:          options.RerankOn,
+		RerankQuery:       options.RerankQuery,
+		IncludeScores:     options.IncludeScores,
+		IncludeSortVector: options.IncludeSortVector,
+	}
+	if commandOptions != (findAndRerankCommandOptions{}) {
+		command.Options = &commandOptions
+	}
+	cursor.payload = map[string]findAndRerankCommand{"findAndRerank": command}
+	return cursor
+}
+
+// FindAndRerank runs a hybrid search (vector and lexical, with the results of both reranked
+// by the reranking service of the collection), returning a cursor over the results.
+// The hybrid sort is required in options. No request is issued until the cursor is first used.
+func (co *Collection) FindAndRerank(filter interface{}, options *FindAndRerankOptions) *RerankCursor[map[string]interface{}] {
+	return co.FindAndRerankContext(context.Background(), filter, options)
+}
+
+// FindAndRerankContext is like FindAndRerank. The given context bounds the request issued by the cursor.
+func (co *Collection) FindAndRerankContext(ctx context.Context, filter interface{}, options *FindAndRerankOptions) *RerankCursor[map[string]interface{}] {
+	return findAndRerank[map[string]interface{}](ctx, co, filter, options)
+}
@@ -0,0 +1,72 @@
+package stragollum
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrLexicalWithVectorSort is returned when a sort combines $lexical with $vector or $vectorize,
+// which the Data API does not support: use FindAndRerank for a hybrid search.
+var ErrLexicalWithVectorSort = errors.New("a lexical sort cannot be combined with a vector sort: use FindAndRerank for hybrid search")
+
+// LexicalSort returns a sort specification for a lexical (BM25) search by the given query,
+// usable as the Sort of the find, update and delete options.
+func LexicalSort(query string) map[string]any {
+	return map[string]any{LexicalField: query}
+}
+
+// LexicalMatch returns a filter matching the documents whose lexical field ($lexical) matches
+// the given query, i.e. {"$lexical": {"$match": query}}.
+func LexicalMatch(query string) map[string]any {
+	return map[string]any{LexicalField: map[string]any{"$match": query}}
+}
+
+// validateSort checks a sort specification for combinations the Data API rejects.
+func validateSort(sort any) error {
+	if sort == nil {
+		return nil
+	}
+	object, _, err := (&encoder{}).encodeObject(sort)
+	if err != nil {
+		return err
+	}
+	var lexical, vector bool
+	for _, field := range object {
+		switch field.key {
+		case LexicalField:
+			lexical = true
+		case VectorField, VectorizeField:
+			vector = true
+		}
+	}
+	if lexical && vector {
+		return ErrLexicalWithVectorSort
+	}
+	return nil
+}
+
+// LexicalSearchOptions holds the optional parameters of Collection.FindByLexical.
+type LexicalSearchOptions struct {
+	Projection any
+	// Limit is the maximum number of documents returned (0 for no limit).
+	Limit int
+}
+
+// FindByLexical runs a lexical (BM25) search, returning a cursor over the documents matching
+// the filter (nil for no filter) in order of relevance to the query. The collection must
+// have lexical search enabled (see CollectionDefinition.WithLexical). options may be nil.
+func (co *Collection) FindByLexical(query string, filter interface{}, options *LexicalSearchOptions) *FindCursor {
+	return co.FindByLexicalContext(context.Background(), query, filter, options)
+}
+
+// FindByLexicalContext is like FindByLexical. The given context bounds every request issued by the cursor.
+func (co *Collection) FindByLexicalContext(ctx context.Context, query string, filter interface{}, options *LexicalSearchOptions) *FindCursor {
+	if options == nil {
+		options = &LexicalSearchOptions{}
+	}
+	return co.FindContext(ctx, filter, &FindOptions{
+		Sort:       LexicalSort(query),
+		Projection: options.Projection,
+		Limit:      options.Limit,
+	})
+}
@@ -0,0 +1,133 @@
+package stragollum
+
+// FindOptions holds the optional parameters of Collection.Find.
+// Sort and Projection accept any value that marshals to the corresponding JSON object
+// (e.g. map[string]any{"field": 1}).
+type FindOptions struct {
+	Sort              any
+	Projection        any
+	Limit             int
+	Skip              int
+	IncludeSimilarity bool
+	IncludeSortVector bool
+}
+
+// FindOneOptions holds the optional parameters of Collection.FindOne.
+type FindOneOptions struct {
+	Sort              any
+	Projection        any
+	IncludeSimilarity bool
+	IncludeSortVector bool
+}
+
+// InsertManyOptions holds the optional parameters of Collection.InsertMany.
+type InsertManyOptions struct {
+	// Ordered makes the insertion sequential, stopping at the first failure.
+	// Otherwise chunks are inserted concurrently and independently.
+	Ordered bool
+	// ChunkSize is the number of documents per request (defaults to DefaultInsertManyChunkSize).
+	ChunkSize int
+	// Concurrency is the maximum number of concurrent requests for unordered insertions
+	// (defaults to DefaultInsertManyConcurrency).
+	Concurrency int
+}
+
+// UpdateOneOptions holds the optional parameters of Collection.UpdateOne.
+type UpdateOneOptions struct {
+	// Upsert inserts a new document if none matches the filter.
+	Upsert bool
+	// Sort selects which document is updated when several match.
+	Sort any
+}
+
+// UpdateManyOptions holds the optional parameters of Collection.UpdateMany.
+type UpdateManyOptions struct {
+	// Upsert inserts a new document if none matches the filter.
+	Upsert bool
+}
+
+// DeleteOneOptions holds the optional parameters of Collection.DeleteOne.
+type DeleteOneOptions struct {
+	// Sort selects which document is deleted when several match.
+	Sort any
+}
+
+// ReturnDocument tells whether the find-and-modify operations return a document
+// as it was before or after the modification.
+type ReturnDocument string
+
+// Constants for ReturnDocument type
+const (
+	ReturnDocumentBefore ReturnDocument = "before"
+	ReturnDocumentAfter  ReturnDocument = "after"
+)
+
+// FindOneAndUpdateOptions holds the optional parameters of Collection.FindOneAndUpdate.
+type FindOneAndUpdateOptions struct {
+	// ReturnDocument defaults to ReturnDocumentBefore.
+	ReturnDocument ReturnDocument
+	Upsert         bool
+	Sort           any
+	Projection     any
+}
+
+// FindOneAndReplaceOptions holds the optional parameters of Collection.FindOneAndReplace.
+type FindOneAndReplaceOptions struct {
+	// ReturnDocument defaults to ReturnDocumentBefore.
+	ReturnDocument ReturnDocument
+	Upsert         bool
+	Sort           any
+	Projection     any
+}
+
+// FindOneAndDeleteOptions holds the optional parameters of Collection.FindOneAndDelete.
+type FindOneAndDeleteOptions struct {
+	Sort       any
+	Projection any
+}
+
+// ReplaceOneOptions holds the optional parameters of Collection.ReplaceOne.
+type ReplaceOneOptions struct {
+	Upsert bool
+	Sort   any
+}
+
+// findCommandOptions is the "options" object of the find/findOne commands.
+type findCommandOptions struct {
+	Limit             int     `json:"limit,omitempty"`
+	Skip              int     `json:"skip,omitempty"`
+	IncludeSimilarity bool    `json:"includeSimilarity,omitempty"`
+	IncludeSortVector bool    `json:"includeSortVector,omitempty"`
+	PageState         *string `json:"pageState,omitempty"`
+}
+
+// findCommand is the body of the find/findOne commands.
+type findCommand struct {
+	Filter     any                 `json:"filter"`
+	Sort       any                 `json:"sort,omitempty"`
+	Projection any                 `json:"projection,omitempty"`
+	Options    *findCommandOptions `json:"options,omitempty"`
+}
+
+// filterObject is a filter as sent in a command: filters matching all documents (see
+// isEmptyFilter), such as nil or nil maps, are sent as the empty object.
+type filterObject struct {
+	filter any
+}
+
+// encodeTree implements treeEncoder.
+func (f filterObject) encodeTree(e *encoder) (any, error) {
+	empty, err := e.isEmptyFilter(f.filter)
+	if err != nil {
+		return nil, err
+	}
+	if empty {
+		return map[string]any{}, nil
+	}
+	return e.encodeValue(f.filter)
+}
+
+// normalizeFilter prepares a filter for a command (see filterObject).
+func normalizeFilter(filter any) any {
+	return filterObject{filter: filter}
+}
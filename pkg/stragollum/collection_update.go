@@ -0,0 +1,134 @@
+package stragollum
+
+import "context"
+
+// UpdateResult reports the outcome of an update operation.
+type UpdateResult struct {
+	MatchedCount  int
+	ModifiedCount int
+	UpsertedCount int
+	// UpsertedID is the ID of the upserted document, or nil if no upsert took place.
+	UpsertedID any
+}
+
+// updateCommandOptions is the "options" object of the updateOne/updateMany commands.
+type updateCommandOptions struct {
+	Upsert    bool    `json:"upsert,omitempty"`
+	PageState *string `json:"pageState,omitempty"`
+}
+
+// updateCommand is the body of the updateOne/updateMany commands.
+type updateCommand struct {
+	Filter  any                   `json:"filter"`
+	Update  any                   `json:"update"`
+	Sort    any                   `json:"sort,omitempty"`
+	Options *updateCommandOptions `json:"options,omitempty"`
+}
+
+// updateStatus is the "status" object returned by the update family of commands.
+type updateStatus struct {
+	MatchedCount  int     `json:"matchedCount"`
+	ModifiedCount int     `json:"modifiedCount"`
+	UpsertedID    any     `json:"upsertedId"`
+	MoreData      bool    `json:"moreData"`
+	NextPageState *string `json:"nextPageState"`
+}
+
+// addTo accumulates the counts of the status into an UpdateResult.
+func (s *updateStatus) addTo(result *UpdateResult) {
+	result.MatchedCount += s.MatchedCount
+	result.ModifiedCount += s.ModifiedCount
+	if s.UpsertedID != nil {
+		result.UpsertedID = s.UpsertedID
+		result.UpsertedCount = 1
+	}
+}
+
+// UpdateOne updates a single document matching the filter (see NewUpdate to build the update).
+// If several documents match, the sort option decides which one is updated. options may be nil.
+func (co *Collection) UpdateOne(filter interface{}, update interface{}, options *UpdateOneOptions) (*UpdateResult, error) {
+	return co.UpdateOneContext(context.Background(), filter, update, options)
+}
+
+// UpdateOneContext is like UpdateOne, with a context bounding the operation.
+func (co *Collection) UpdateOneContext(ctx context.Context, filter interface{}, update interface{}, options *UpdateOneOptions) (*UpdateResult, error) {
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	if options == nil {
+		options = &UpdateOneOptions{}
+	}
+	if err := validateSort(options.Sort); err != nil {
+		return nil, err
+	}
+	command := updateCommand{
+		Filter: normalizeFilter(filter),
+		Update: update,
+		Sort:   options.Sort,
+	}
+	if options.Upsert {
+		command.Options = &updateCommandOptions{Upsert: true}
+	}
+	requestPayload := struct {
+		UpdateOne updateCommand `json:"updateOne"`
+	}{
+		UpdateOne: command,
+	}
+
+	var response struct {
+		Status updateStatus `json:"status"`
+	}
+	if err := co.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+		return nil, err
+	}
+
+	result := &UpdateResult{}
+	response.Status.addTo(result)
+	return result, nil
+}
+
+// UpdateMany updates all documents matching the filter (see NewUpdate to build the update).
+// As the API processes the matches in pages, several requests may be issued
+// until the whole filter is processed: the returned counts are the totals. options may be nil.
+func (co *Collection) UpdateMany(filter interface{}, update interface{}, options *UpdateManyOptions) (*UpdateResult, error) {
+	return co.UpdateManyContext(context.Background(), filter, update, options)
+}
+
+// UpdateManyContext is like UpdateMany, with a context bounding the whole operation.
+func (co *Collection) UpdateManyContext(ctx context.Context, filter interface{}, update interface{}, options *UpdateManyOptions) (*UpdateResult, error) {
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	if options == nil {
+		options = &UpdateManyOptions{}
+	}
+	result := &UpdateResult{}
+	var pageState *string
+	for {
+		command := updateCommand{
+			Filter: normalizeFilter(filter),
+			Update: update,
+		}
+		if options.Upsert || pageState != nil {
+			command.Options = &updateCommandOptions{Upsert: options.Upsert, PageState: pageState}
+		}
+		requestPayload := struct {
+			UpdateMany updateCommand `json:"updateMany"`
+		}{
+			UpdateMany: command,
+		}
+
+		var response struct {
+			Status updateStatus `json:"status"`
+		}
+		if err := co.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+			return result, err
+		}
+		response.Status.addTo(result)
+
+		if !response.Status.MoreData || response.Status.NextPageState == nil {
+			return result, nil
+		}
+		pageState = response.Status.NextPageState
+	}
+}
@@ -0,0 +1,158 @@
+package stragollum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Reserved document fields.
+const (
+	// IDField is the document ID.
+	IDField = "_id"
+	// VectorField holds the vector (embedding) of a document.
+	VectorField = "$vector"
+	// VectorizeField holds the text from which the vector of a document is computed
+	// by the embedding service configured for the collection.
+	VectorizeField = "$vectorize"
+	// SimilarityField holds the similarity score of a document returned by a vector search.
+	SimilarityField = "$similarity"
+)
+
+// SetVector sets the vector of a document (as a DataAPIVector), creating the document if nil,
+// and returns it.
+func SetVector(document map[string]interface{}, vector []float32) map[string]interface{} {
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	document[VectorField] = DataAPIVector(vector)
+	return document
+}
+
+// SetVectorize sets the text to compute the vector of a document from, creating the
+// document if nil, and returns it.
+func SetVectorize(document map[string]interface{}, text string) map[string]interface{} {
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	document[VectorizeField] = text
+	return document
+}
+
+// VectorSort returns a sort specification for a vector search by the given vector,
+// usable as the Sort of the find, update and delete options. The vector is a DataAPIVector.
+func VectorSort(vector []float32) map[string]any {
+	return map[string]any{VectorField: DataAPIVector(vector)}
+}
+
+// VectorizeSort returns a sort specification for a vector search by the vector computed
+// from the given text, usable as the Sort of the find, update and delete options.
+func VectorizeSort(text string) map[string]any {
+	return map[string]any{VectorizeField: text}
+}
+
+// VectorSearchOptions holds the optional parameters of Collection.FindByVector and Collection.FindByVectorize.
+type VectorSearchOptions struct {
+	Projection any
+	// IncludeSortVector makes the result carry the vector used for the search
+	// (for FindByVectorize, the one computed by the embedding service).
+	IncludeSortVector bool
+}
+
+// ScoredDocument is a document returned by a vector search, with its similarity score.
+type ScoredDocument[T any] struct {
+	Document   T
+	Similarity float64
+}
+
+// VectorSearchResult holds the documents returned by a vector search, most similar first.
+type VectorSearchResult[T any] struct {
+	Documents []ScoredDocument[T]
+	// SortVector is the vector used for the search, if requested with IncludeSortVector.
+	SortVector []float32
+}
+
+// documentSimilarity extracts the similarity score from a raw document.
+func documentSimilarity(raw json.RawMessage) (float64, error) {
+	var scored struct {
+		Similarity *float64 `json:"$similarity"`
+	}
+	if err := json.Unmarshal(raw, &scored); err != nil {
+		return 0, fmt.Errorf("failed to decode similarity: %w", err)
+	}
+	if scored.Similarity == nil {
+		return 0, fmt.Errorf("document has no %s field", SimilarityField)
+	}
+	return *scored.Similarity, nil
+}
+
+// vectorSearch runs a find sorted by the given vector sort, returning up to k documents
+// decoded as T along with their similarity.
+func vectorSearch[T any](ctx context.Context, co *Collection, sort map[string]any, k int, filter interface{}, options *VectorSearchOptions) (*VectorSearchResult[T], error) {
+	if k <= 0 {
+		return nil, fmt.Errorf("invalid number of results for a vector search: %d", k)
+	}
+	if options == nil {
+		options = &VectorSearchOptions{}
+	}
+
+	ctx, cancel := co.commander.operationContext(ctx)
+	defer cancel()
+
+	cursor := co.FindContext(ctx, filter, &FindOptions{
+		Sort:              sort,
+		Projection:        options.Projection,
+		Limit:             k,
+		IncludeSimilarity: true,
+		IncludeSortVector: options.IncludeSortVector,
+	})
+	defer cursor.Close()
+
+	result := &VectorSearchResult[T]{Documents: []ScoredDocument[T]{}}
+	for cursor.Next() {
+		var scored ScoredDocument[T]
+		if err := cursor.Decode(&scored.Document); err != nil {
+			return nil, err
+		}
+		similarity, err := cursor.Similarity()
+		if err != nil {
+			return nil, err
+		}
+		scored.Similarity = similarity
+		result.Documents = append(result.Documents, scored)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	if options.IncludeSortVector {
+		sortVector, err := cursor.SortVector()
+		if err != nil {
+			return nil, err
+		}
+		result.SortVector = sortVector
+	}
+	return result, nil
+}
+
+// FindByVector runs a vector search, returning the (up to) k documents matching the filter
+// (nil for no filter) whose vector is most similar to the given one, with their similarity.
+// options may be nil.
+func (co *Collection) FindByVector(vector []float32, k int, filter interface{}, options *VectorSearchOptions) (*VectorSearchResult[map[string]interface{}], error) {
+	return co.FindByVectorContext(context.Background(), vector, k, filter, options)
+}
+
+// FindByVectorContext is like FindByVector, with a context bounding the operation.
+func (co *Collection) FindByVectorContext(ctx context.Context, vector []float32, k int, filter interface{}, options *VectorSearchOptions) (*VectorSearchResult[map[string]interface{}], error) {
+	return vectorSearch[map[string]interface{}](ctx, co, VectorSort(vector), k, filter, options)
+}
+
+// FindByVectorize is like FindByVector, the search vector being computed from the given
+// text by the embedding service configured for the collection.
+func (co *Collection) FindByVectorize(text string, k int, filter interface{}, options *VectorSearchOptions) (*VectorSearchResult[map[string]interface{}], error) {
+	return co.FindByVectorizeContext(context.Background(), text, k, filter, options)
+}
+
+// FindByVectorizeContext is like FindByVectorize, with a context bounding the operation.
+func (co *Collection) FindByVectorizeContext(ctx context.Context, text string, k int, filter interface{}, options *VectorSearchOptions) (*VectorSearchResult[map[string]interface{}], error) {
+	return vectorSearch[map[string]interface{}](ctx, co, VectorizeSort(text), k, filter, options)
+}
@@ -0,0 +1,235 @@
+package stragollum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrCursorClosed is returned when using a cursor after it has been closed.
+var ErrCursorClosed = errors.New("cursor is closed")
+
+// ErrNoCurrentDocument is returned when decoding from a cursor not positioned on a document.
+var ErrNoCurrentDocument = errors.New("cursor has no current document: call Next first")
+
+// cursorPage is a single page of results, as returned by the API.
+type cursorPage struct {
+	documents     []json.RawMessage
+	nextPageState *string
+	sortVector    []float32
+	// schema describes the columns of the rows, for the results read from tables
+	schema tableSchema
+}
+
+// pageFetcher retrieves the page of results identified by pageState (nil for the first page).
+type pageFetcher func(ctx context.Context, pageState *string) (*cursorPage, error)
+
+// FindCursor iterates over the results of a find operation, fetching the
+// pages of results from the API as needed.
+//
+// Typical usage:
+//
+//	cursor := collection.Find(filter, nil)
+//	defer cursor.Close()
+//	for cursor.Next() {
+//		var doc map[string]any
+//		if err := cursor.Decode(&doc); err != nil { ... }
+//	}
+//	if err := cursor.Err(); err != nil { ... }
+type FindCursor struct {
+	ctx       context.Context
+	commander *DataAPICommander
+	fetch     pageFetcher
+	limit     int
+
+	buffer        []json.RawMessage
+	current       json.RawMessage
+	nextPageState *string
+	started       bool
+	exhausted     bool
+	closed        bool
+	err           error
+	sortVector    []float32
+	consumed      int
+	// rows tells whether the results are table rows, decoded according to their schema
+	rows   bool
+	schema tableSchema
+}
+
+func newFindCursor(ctx context.Context, commander *DataAPICommander, fetch pageFetcher, limit int) *FindCursor {
+	return &FindCursor{
+		ctx:       ctx,
+		commander: commander,
+		fetch:     fetch,
+		limit:     limit,
+	}
+}
+
+// fetchPage retrieves the next page of results into the buffer.
+func (c *FindCursor) fetchPage(ctx context.Context) error {
+	page, err := c.fetch(ctx, c.nextPageState)
+	if err != nil {
+		return err
+	}
+	if !c.started {
+		c.sortVector = page.sortVector
+		c.schema = page.schema
+		c.started = true
+	}
+	c.buffer = append(c.buffer, page.documents...)
+	c.nextPageState = page.nextPageState
+	if page.nextPageState == nil || *page.nextPageState == "" {
+		c.exhausted = true
+	}
+	return nil
+}
+
+// next advances the cursor, issuing requests with the given context if needed.
+func (c *FindCursor) next(ctx context.Context) bool {
+	c.current = nil
+	if c.closed || c.err != nil {
+		return false
+	}
+	if c.limit > 0 && c.consumed >= c.limit {
+		return false
+	}
+	for len(c.buffer) == 0 {
+		if c.exhausted {
+			return false
+		}
+		if err := c.fetchPage(ctx); err != nil {
+			c.err = err
+			return false
+		}
+	}
+	c.current = c.buffer[0]
+	c.buffer = c.buffer[1:]
+	c.consumed++
+	return true
+}
+
+// Next advances the cursor to the next document, fetching a new page if necessary.
+// It returns false when the results are exhausted or an error occurred (check Err).
+func (c *FindCursor) Next() bool {
+	return c.next(c.ctx)
+}
+
+// Decode decodes the current document into v.
+func (c *FindCursor) Decode(v interface{}) error {
+	if c.closed {
+		return ErrCursorClosed
+	}
+	if c.current == nil {
+		return ErrNoCurrentDocument
+	}
+	if c.rows {
+		// Rows are decoded according to their schema, whatever the number decoding setting
+		if err := decodeRow(c.current, c.schema, v); err != nil {
+			return fmt.Errorf("failed to decode row: %w", err)
+		}
+		return nil
+	}
+	return c.commander.decodeDocument(c.current, v)
+}
+
+// Document returns the current document as an untyped map.
+func (c *FindCursor) Document() (map[string]interface{}, error) {
+	var document map[string]interface{}
+	if err := c.Decode(&document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// Similarity returns the similarity score of the current document, as returned by a vector
+// search run with the IncludeSimilarity option (an error is returned if the score is missing).
+func (c *FindCursor) Similarity() (float64, error) {
+	if c.closed {
+		return 0, ErrCursorClosed
+	}
+	if c.current == nil {
+		return 0, ErrNoCurrentDocument
+	}
+	return documentSimilarity(c.current)
+}
+
+// All decodes all the remaining documents into results, which must be a pointer to a slice,
+// then closes the cursor. The whole iteration is bounded by the operation timeout, if any.
+func (c *FindCursor) All(results interface{}) error {
+	defer c.Close()
+
+	sliceValue := reflect.ValueOf(results)
+	if sliceValue.Kind() != reflect.Ptr || sliceValue.IsNil() || sliceValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("results must be a pointer to a slice, got %T", results)
+	}
+	sliceValue = sliceValue.Elem()
+	elemType := sliceValue.Type().Elem()
+
+	ctx, cancel := c.commander.operationContext(c.ctx)
+	defer cancel()
+	for c.next(ctx) {
+		elem := reflect.New(elemType)
+		if err := c.Decode(elem.Interface()); err != nil {
+			return err
+		}
+		sliceValue.Set(reflect.Append(sliceValue, elem.Elem()))
+	}
+	return c.Err()
+}
+
+// Iter returns a function iterating over the remaining documents, in the shape of an iter.Seq2:
+// with Go 1.23 or later it can be used as `for doc, err := range cursor.Iter()`.
+// Iteration stops after the first error.
+func (c *FindCursor) Iter() func(yield func(map[string]interface{}, error) bool) {
+	return func(yield func(map[string]interface{}, error) bool) {
+		for c.Next() {
+			document, err := c.Document()
+			if !yield(document, err) || err != nil {
+				return
+			}
+		}
+		if err := c.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (c *FindCursor) Err() error {
+	return c.err
+}
+
+// Close closes the cursor, discarding any buffered document. It always returns nil.
+func (c *FindCursor) Close() error {
+	c.closed = true
+	c.buffer = nil
+	c.current = nil
+	return nil
+}
+
+// Buffered returns the number of documents fetched from the API but not yet consumed.
+func (c *FindCursor) Buffered() int {
+	return len(c.buffer)
+}
+
+// Consumed returns the number of documents the cursor has advanced through so far.
+func (c *FindCursor) Consumed() int {
+	return c.consumed
+}
+
+// SortVector returns the vector used for sorting, as returned by the API when the
+// IncludeSortVector find option is set (nil otherwise). If no page has been fetched yet,
+// the first page is fetched (without consuming any document).
+func (c *FindCursor) SortVector() ([]float32, error) {
+	if c.closed {
+		return nil, ErrCursorClosed
+	}
+	if !c.started && c.err == nil {
+		if err := c.fetchPage(c.ctx); err != nil {
+			c.err = err
+		}
+	}
+	return c.sortVector, c.err
+}
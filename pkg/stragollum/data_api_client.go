@@ -15,8 +15,11 @@ const (
 )
 
 type DataAPIClient struct {
-	environment Environment // Use value, not pointer
-	token       *string     // token can be nil
+	environment    Environment // Use value, not pointer
+	token          *string     // token can be nil
+	timeoutOptions TimeoutOptions
+	vectorEncoding VectorEncoding
+	numberDecoding NumberDecoding
 }
 
 // NewDataAPIClient creates a new DataAPIClient.
@@ -28,8 +31,10 @@ func NewDataAPIClient(environment *Environment, token *string) *DataAPIClient {
 		env = *environment
 	}
 	return &DataAPIClient{
-		environment: env,
-		token:       token,
+		environment:    env,
+		token:          token,
+		vectorEncoding: DefaultVectorEncoding,
+		numberDecoding: DefaultNumberDecoding,
 	}
 }
 
@@ -43,6 +48,45 @@ func (c *DataAPIClient) Token() *string {
 	return c.token
 }
 
+// TimeoutOptions returns the client's default timeouts.
+func (c *DataAPIClient) TimeoutOptions() TimeoutOptions {
+	return c.timeoutOptions
+}
+
+// WithTimeoutOptions sets the default timeouts, inherited by the databases (and, in turn, collections)
+// spawned by the client from now on.
+func (c *DataAPIClient) WithTimeoutOptions(timeoutOptions TimeoutOptions) *DataAPIClient {
+	c.timeoutOptions = timeoutOptions
+	return c
+}
+
+// VectorEncoding returns the client's serialization of DataAPIVector values.
+func (c *DataAPIClient) VectorEncoding() VectorEncoding {
+	return c.vectorEncoding
+}
+
+// WithVectorEncoding sets the serialization of DataAPIVector values in the requests (see
+// VectorEncoding), inherited by the databases (and, in turn, collections) spawned by the
+// client from now on.
+func (c *DataAPIClient) WithVectorEncoding(vectorEncoding VectorEncoding) *DataAPIClient {
+	c.vectorEncoding = vectorEncoding
+	return c
+}
+
+// NumberDecoding returns the client's representation of the numbers of the documents read
+// into untyped values.
+func (c *DataAPIClient) NumberDecoding() NumberDecoding {
+	return c.numberDecoding
+}
+
+// WithNumberDecoding sets the representation of the numbers of the documents read into
+// untyped values (see NumberDecoding), inherited by the databases (and, in turn,
+// collections) spawned by the client from now on. Tables ignore it (see Table).
+func (c *DataAPIClient) WithNumberDecoding(numberDecoding NumberDecoding) *DataAPIClient {
+	c.numberDecoding = numberDecoding
+	return c
+}
+
 // GetDatabase creates a Database instance with the given apiEndpoint, optional token, and optional keyspace.
 // If token is nil, uses the DataAPIClient's token. If keyspace is empty, uses the default DefaultKeyspace.
 // It also initializes and embeds a DataAPICommander.
@@ -57,7 +101,10 @@ func (c *DataAPIClient) GetDatabase(apiEndpoint string, token *string, keyspace
 	}
 
 	commanderURL := fmt.Sprintf("%s/api/json/v1/%s", apiEndpoint, finalKeyspace)
-	commander := NewDataAPICommander(commanderURL, finalToken)
+	commander := NewDataAPICommander(commanderURL, finalToken).
+		WithTimeoutOptions(c.timeoutOptions).
+		WithVectorEncoding(c.vectorEncoding).
+		WithNumberDecoding(c.numberDecoding)
 
 	return &Database{
 		apiEndpoint: apiEndpoint,
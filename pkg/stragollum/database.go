@@ -1,6 +1,9 @@
 package stragollum
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // Database represents a connection to a specific database/keyspace via the Data API.
 type Database struct {
@@ -30,9 +33,55 @@ func (db *Database) Commander() *DataAPICommander {
 	return db.commander
 }
 
+// TimeoutOptions returns the Database's default timeouts.
+func (db *Database) TimeoutOptions() TimeoutOptions {
+	return db.commander.TimeoutOptions()
+}
+
+// WithTimeoutOptions sets the Database's default timeouts, also inherited by
+// the collections obtained from it from now on.
+func (db *Database) WithTimeoutOptions(timeoutOptions TimeoutOptions) *Database {
+	db.commander.WithTimeoutOptions(timeoutOptions)
+	return db
+}
+
+// VectorEncoding returns the Database's serialization of DataAPIVector values.
+func (db *Database) VectorEncoding() VectorEncoding {
+	return db.commander.VectorEncoding()
+}
+
+// WithVectorEncoding sets the Database's serialization of DataAPIVector values in the
+// requests, also inherited by the collections obtained from it from now on.
+func (db *Database) WithVectorEncoding(vectorEncoding VectorEncoding) *Database {
+	db.commander.WithVectorEncoding(vectorEncoding)
+	return db
+}
+
+// NumberDecoding returns the Database's representation of the numbers of the documents
+// read into untyped values.
+func (db *Database) NumberDecoding() NumberDecoding {
+	return db.commander.NumberDecoding()
+}
+
+// WithNumberDecoding sets the Database's representation of the numbers of the documents
+// read into untyped values, also inherited by the collections obtained from it from now on.
+// Tables ignore it (see Table).
+func (db *Database) WithNumberDecoding(numberDecoding NumberDecoding) *Database {
+	db.commander.WithNumberDecoding(numberDecoding)
+	return db
+}
+
 // ListCollectionNames retrieves the collection names in the database/keyspace.
 // It returns a slice of strings containing the collection names, or an error if the request fails.
 func (db *Database) ListCollectionNames() ([]string, error) {
+	return db.ListCollectionNamesContext(context.Background())
+}
+
+// ListCollectionNamesContext is like ListCollectionNames, with a context bounding the operation.
+func (db *Database) ListCollectionNamesContext(ctx context.Context) ([]string, error) {
+	ctx, cancel := db.commander.operationContext(ctx)
+	defer cancel()
+
 	// Create the request payload as per API requirements
 	requestPayload := struct {
 		FindCollections struct{} `json:"findCollections"`
@@ -48,7 +97,7 @@ func (db *Database) ListCollectionNames() ([]string, error) {
 	}{}
 
 	// Send the request and parse the response
-	err := db.commander.Request(requestPayload, &responseData)
+	err := db.commander.RequestContext(ctx, requestPayload, &responseData)
 	if err != nil {
 		return nil, err
 	}
@@ -57,9 +106,85 @@ func (db *Database) ListCollectionNames() ([]string, error) {
 	return responseData.Status.Collections, nil
 }
 
+// CollectionDescriptor describes a collection: its name and its definition.
+type CollectionDescriptor struct {
+	Name       string               `json:"name"`
+	Definition CollectionDefinition `json:"options"`
+}
+
+// ListCollections retrieves the collections in the database/keyspace, with their definitions.
+func (db *Database) ListCollections() ([]CollectionDescriptor, error) {
+	return db.ListCollectionsContext(context.Background())
+}
+
+// ListCollectionsContext is like ListCollections, with a context bounding the operation.
+func (db *Database) ListCollectionsContext(ctx context.Context) ([]CollectionDescriptor, error) {
+	ctx, cancel := db.commander.operationContext(ctx)
+	defer cancel()
+
+	// The payload is {"findCollections": {"options": {"explain": true}}}
+	requestPayload := struct {
+		FindCollections struct {
+			Options struct {
+				Explain bool `json:"explain"`
+			} `json:"options"`
+		} `json:"findCollections"`
+	}{}
+	requestPayload.FindCollections.Options.Explain = true
+
+	// The API returns: {"status": {"collections": [{"name": <name>, "options": <definition>}]}}
+	var response struct {
+		Status struct {
+			Collections []CollectionDescriptor `json:"collections"`
+		} `json:"status"`
+	}
+
+	err := db.commander.RequestContext(ctx, requestPayload, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Status.Collections, nil
+}
+
+// CreateCollectionOptions holds the optional parameters of Database.CreateCollection.
+type CreateCollectionOptions struct {
+	// CheckExists makes the creation idempotent: if a collection with the same name
+	// exists, it is returned as long as its definition matches the requested one,
+	// otherwise a *CollectionDefinitionMismatchError is returned.
+	CheckExists bool
+}
+
 // CreateCollection creates a new collection with the given name and definition (as options).
 // Returns an error if the API response is not {"status": {"ok": 1}} or if the request fails.
-func (db *Database) CreateCollection(name string, definition *CollectionDefinition) (*Collection, error) {
+func (db *Database) CreateCollection(name string, definition *CollectionDefinition, options ...*CreateCollectionOptions) (*Collection, error) {
+	return db.CreateCollectionContext(context.Background(), name, definition, options...)
+}
+
+// CreateCollectionContext is like CreateCollection, with a context bounding the operation.
+func (db *Database) CreateCollectionContext(ctx context.Context, name string, definition *CollectionDefinition, options ...*CreateCollectionOptions) (*Collection, error) {
+	ctx, cancel := db.commander.operationContext(ctx)
+	defer cancel()
+
+	if len(options) > 0 && options[0] != nil && options[0].CheckExists {
+		descriptors, err := db.ListCollectionsContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for an existing collection: %w", err)
+		}
+		for _, descriptor := range descriptors {
+			if descriptor.Name != name {
+				continue
+			}
+			requested := CollectionDefinition{}
+			if definition != nil {
+				requested = *definition
+			}
+			if differences := requested.differences(descriptor.Definition); len(differences) > 0 {
+				return nil, &CollectionDefinitionMismatchError{Name: name, Differences: differences}
+			}
+			return db.GetCollection(name, nil), nil
+		}
+	}
+
 	// Prepare the payload as per API spec
 	type inner struct {
 		Name    string                `json:"name"`
@@ -81,7 +206,7 @@ func (db *Database) CreateCollection(name string, definition *CollectionDefiniti
 		} `json:"status"`
 	}
 
-	err := db.commander.Request(payload, &response)
+	err := db.commander.RequestContext(ctx, payload, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -97,6 +222,14 @@ func (db *Database) CreateCollection(name string, definition *CollectionDefiniti
 // DropCollection drops the collection with the given name.
 // Returns an error if the API response is not {"status": {"ok": 1}} or if the request fails.
 func (db *Database) DropCollection(name string) error {
+	return db.DropCollectionContext(context.Background(), name)
+}
+
+// DropCollectionContext is like DropCollection, with a context bounding the operation.
+func (db *Database) DropCollectionContext(ctx context.Context, name string) error {
+	ctx, cancel := db.commander.operationContext(ctx)
+	defer cancel()
+
 	// Prepare the payload as per API spec
 	type inner struct {
 		Name string `json:"name"`
@@ -116,7 +249,7 @@ func (db *Database) DropCollection(name string) error {
 		} `json:"status"`
 	}
 
-	err := db.commander.Request(payload, &response)
+	err := db.commander.RequestContext(ctx, payload, &response)
 	if err != nil {
 		return err
 	}
@@ -136,7 +269,10 @@ func (d *Database) GetCollection(name string, token *string) *Collection {
 	}
 
 	commanderURL := fmt.Sprintf("%s/api/json/v1/%s/%s", d.ApiEndpoint(), d.Keyspace(), name)
-	commander := NewDataAPICommander(commanderURL, finalToken)
+	commander := NewDataAPICommander(commanderURL, finalToken).
+		WithTimeoutOptions(d.TimeoutOptions()).
+		WithVectorEncoding(d.VectorEncoding()).
+		WithNumberDecoding(d.NumberDecoding())
 
 	return &Collection{
 		apiEndpoint: d.ApiEndpoint(),
@@ -144,5 +280,6 @@ func (d *Database) GetCollection(name string, token *string) *Collection {
 		token:       finalToken,
 		keyspace:    d.Keyspace(),
 		commander:   commander,
+		database:    d,
 	}
 }
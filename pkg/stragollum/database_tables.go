@@ -0,0 +1,220 @@
+package stragollum
+
+import (
+	"context"
+	"fmt"
+)
+
+// TableDescriptor describes a table: its name and its definition.
+type TableDescriptor struct {
+	Name       string          `json:"name"`
+	Definition TableDefinition `json:"definition"`
+}
+
+// CreateTableOptions holds the optional parameters of Database.CreateTable.
+type CreateTableOptions struct {
+	// IfNotExists makes the creation succeed (without changes) if the table already exists.
+	IfNotExists bool
+}
+
+// DropTableOptions holds the optional parameters of Database.DropTable.
+type DropTableOptions struct {
+	// IfExists makes the drop succeed if the table does not exist.
+	IfExists bool
+}
+
+// GetTable returns the Table with the given name, inheriting the settings of the Database.
+// The token, if not nil, overrides the Database's.
+func (db *Database) GetTable(name string, token *string) *Table {
+	finalToken := db.token
+	if token != nil {
+		finalToken = token
+	}
+
+	commanderURL := fmt.Sprintf("%s/api/json/v1/%s/%s", db.ApiEndpoint(), db.Keyspace(), name)
+	commander := NewDataAPICommander(commanderURL, finalToken).
+		WithTimeoutOptions(db.TimeoutOptions()).
+		WithVectorEncoding(db.VectorEncoding()).
+		WithNumberDecoding(db.NumberDecoding())
+	commander.tables = true
+
+	return &Table{
+		apiEndpoint: db.ApiEndpoint(),
+		name:        name,
+		token:       finalToken,
+		keyspace:    db.Keyspace(),
+		commander:   commander,
+		database:    db,
+	}
+}
+
+// CreateTable creates a new table with the given name and definition.
+// Returns an error if the definition is invalid, if the API response is not
+// {"status": {"ok": 1}} or if the request fails.
+func (db *Database) CreateTable(name string, definition *TableDefinition, options ...*CreateTableOptions) (*Table, error) {
+	return db.CreateTableContext(context.Background(), name, definition, options...)
+}
+
+// CreateTableContext is like CreateTable, with a context bounding the operation.
+func (db *Database) CreateTableContext(ctx context.Context, name string, definition *TableDefinition, options ...*CreateTableOptions) (*Table, error) {
+	ctx, cancel := db.commander.operationContext(ctx)
+	defer cancel()
+
+	if definition == nil {
+		return nil, fmt.Errorf("missing table definition")
+	}
+	if err := definition.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid table definition: %w", err)
+	}
+
+	// The payload is {"createTable": {"name": <name>, "definition": <definition>, "options": {...}}}
+	type createOptions struct {
+		IfNotExists bool `json:"ifNotExists,omitempty"`
+	}
+	type inner struct {
+		Name       string           `json:"name"`
+		Definition *TableDefinition `json:"definition"`
+		Options    *createOptions   `json:"options,omitempty"`
+	}
+	payload := struct {
+		CreateTable inner `json:"createTable"`
+	}{
+		CreateTable: inner{Name: name, Definition: definition},
+	}
+	if len(options) > 0 && options[0] != nil && options[0].IfNotExists {
+		payload.CreateTable.Options = &createOptions{IfNotExists: true}
+	}
+
+	if err := db.commander.requestOk(ctx, payload); err != nil {
+		return nil, err
+	}
+	return db.GetTable(name, nil), nil
+}
+
+// DropTable drops the table with the given name.
+// Returns an error if the API response is not {"status": {"ok": 1}} or if the request fails.
+func (db *Database) DropTable(name string, options ...*DropTableOptions) error {
+	return db.DropTableContext(context.Background(), name, options...)
+}
+
+// DropTableContext is like DropTable, with a context bounding the operation.
+func (db *Database) DropTableContext(ctx context.Context, name string, options ...*DropTableOptions) error {
+	ctx, cancel := db.commander.operationContext(ctx)
+	defer cancel()
+
+	type dropOptions struct {
+		IfExists bool `json:"ifExists,omitempty"`
+	}
+	type inner struct {
+		Name    string       `json:"name"`
+		Options *dropOptions `json:"options,omitempty"`
+	}
+	payload := struct {
+		DropTable inner `json:"dropTable"`
+	}{
+		DropTable: inner{Name: name},
+	}
+	if len(options) > 0 && options[0] != nil && options[0].IfExists {
+		payload.DropTable.Options = &dropOptions{IfExists: true}
+	}
+
+	return db.commander.requestOk(ctx, payload)
+}
+
+// DropIndexOptions holds the optional parameters of Database.DropIndex.
+type DropIndexOptions struct {
+	// IfExists makes the drop succeed if the index does not exist.
+	IfExists bool
+}
+
+// DropIndex drops the table index with the given name from the keyspace.
+// Returns an error if the API response is not {"status": {"ok": 1}} or if the request fails.
+func (db *Database) DropIndex(name string, options ...*DropIndexOptions) error {
+	return db.DropIndexContext(context.Background(), name, options...)
+}
+
+// DropIndexContext is like DropIndex, with a context bounding the operation.
+func (db *Database) DropIndexContext(ctx context.Context, name string, options ...*DropIndexOptions) error {
+	ctx, cancel := db.commander.operationContext(ctx)
+	defer cancel()
+
+	type dropOptions struct {
+		IfExists bool `json:"ifExists,omitempty"`
+	}
+	type inner struct {
+		Name    string       `json:"name"`
+		Options *dropOptions `json:"options,omitempty"`
+	}
+	payload := struct {
+		DropIndex inner `json:"dropIndex"`
+	}{
+		DropIndex: inner{Name: name},
+	}
+	if len(options) > 0 && options[0] != nil && options[0].IfExists {
+		payload.DropIndex.Options = &dropOptions{IfExists: true}
+	}
+
+	return db.commander.requestOk(ctx, payload)
+}
+
+// ListTableNames retrieves the table names in the database/keyspace.
+func (db *Database) ListTableNames() ([]string, error) {
+	return db.ListTableNamesContext(context.Background())
+}
+
+// ListTableNamesContext is like ListTableNames, with a context bounding the operation.
+func (db *Database) ListTableNamesContext(ctx context.Context) ([]string, error) {
+	ctx, cancel := db.commander.operationContext(ctx)
+	defer cancel()
+
+	requestPayload := struct {
+		ListTables struct{} `json:"listTables"`
+	}{}
+
+	// The API returns: {"status": {"tables": [<name>, ...]}}
+	var response struct {
+		Status struct {
+			Tables []string `json:"tables"`
+		} `json:"status"`
+	}
+
+	err := db.commander.RequestContext(ctx, requestPayload, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Status.Tables, nil
+}
+
+// ListTables retrieves the tables in the database/keyspace, with their definitions.
+func (db *Database) ListTables() ([]TableDescriptor, error) {
+	return db.ListTablesContext(context.Background())
+}
+
+// ListTablesContext is like ListTables, with a context bounding the operation.
+func (db *Database) ListTablesContext(ctx context.Context) ([]TableDescriptor, error) {
+	ctx, cancel := db.commander.operationContext(ctx)
+	defer cancel()
+
+	// The payload is {"listTables": {"options": {"explain": true}}}
+	requestPayload := struct {
+		ListTables struct {
+			Options struct {
+				Explain bool `json:"explain"`
+			} `json:"options"`
+		} `json:"listTables"`
+	}{}
+	requestPayload.ListTables.Options.Explain = true
+
+	// The API returns: {"status": {"tables": [{"name": <name>, "definition": <definition>}]}}
+	var response struct {
+		Status struct {
+			Tables []TableDescriptor `json:"tables"`
+		} `json:"status"`
+	}
+
+	err := db.commander.RequestContext(ctx, requestPayload, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Status.Tables, nil
+}
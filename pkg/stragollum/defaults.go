@@ -2,3 +2,16 @@ package stragollum
 
 // DefaultKeyspace is the default keyspace used when none is provided.
 const DefaultKeyspace = "default_keyspace"
+
+// DefaultInsertManyChunkSize is the default number of documents sent with each insertMany request.
+const DefaultInsertManyChunkSize = 50
+
+// DefaultInsertManyConcurrency is the default number of concurrent requests for unordered insertions.
+const DefaultInsertManyConcurrency = 20
+
+// DefaultVectorEncoding is the default serialization of DataAPIVector values in the requests.
+const DefaultVectorEncoding = VectorEncodingArray
+
+// DefaultNumberDecoding is the default representation of the numbers of the documents read
+// into untyped values.
+const DefaultNumberDecoding = NumberDecodingFloat64
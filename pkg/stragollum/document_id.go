@@ -0,0 +1,213 @@
+package stragollum
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Document ID types, for the defaultId option of collections (see
+// CollectionDefinition.WithDefaultID) and for the local generation of IDs (see
+// Collection.WithIDGeneration).
+const (
+	DefaultIDTypeUUID     = "uuid"
+	DefaultIDTypeUUIDv6   = "uuidv6"
+	DefaultIDTypeUUIDv7   = "uuidv7"
+	DefaultIDTypeObjectID = "objectId"
+)
+
+// DocumentIDKind tells which kind of value a DocumentID holds.
+type DocumentIDKind int
+
+// Document ID kinds.
+const (
+	DocumentIDNone DocumentIDKind = iota
+	DocumentIDString
+	DocumentIDNumber
+	DocumentIDBoolean
+	DocumentIDDate
+	DocumentIDUUID
+	DocumentIDObjectID
+)
+
+// DocumentID is the _id of a document, which can be a string, a number, a boolean, a
+// date, a UUID or an ObjectID. It is what the insert operations return, whatever the
+// defaultId of the collection; it can be used as the _id of filters, e.g.
+//
+//	collection.FindOne(map[string]any{stragollum.IDField: id})
+//
+// The zero DocumentID holds no value.
+type DocumentID struct {
+	// value is a string, json.Number, bool, time.Time, UUID or ObjectID
+	value interface{}
+}
+
+// NewDocumentID creates a DocumentID holding the given value: a string, a number (of any
+// numeric type, or json.Number), a bool, a time.Time, a UUID or an ObjectID. Pointers to
+// these are dereferenced.
+func NewDocumentID(value interface{}) (DocumentID, error) {
+	switch v := value.(type) {
+	case DocumentID:
+		return v, nil
+	case string, bool, UUID, ObjectID:
+		return DocumentID{value: v}, nil
+	case json.Number:
+		if _, err := v.Float64(); err != nil {
+			return DocumentID{}, fmt.Errorf("invalid document ID %q: %w", v, err)
+		}
+		return DocumentID{value: v}, nil
+	case time.Time:
+		return DocumentID{value: v.UTC()}, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if !rv.IsNil() {
+			return NewDocumentID(rv.Elem().Interface())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return DocumentID{value: json.Number(strconv.FormatInt(rv.Int(), 10))}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return DocumentID{value: json.Number(strconv.FormatUint(rv.Uint(), 10))}, nil
+	case reflect.Float32, reflect.Float64:
+		encoded, err := json.Marshal(rv.Interface())
+		if err != nil {
+			return DocumentID{}, fmt.Errorf("invalid document ID: %w", err)
+		}
+		return DocumentID{value: json.Number(encoded)}, nil
+	}
+	return DocumentID{}, fmt.Errorf("unsupported type for a document ID: %T", value)
+}
+
+// Kind returns the kind of value held by the DocumentID.
+func (id DocumentID) Kind() DocumentIDKind {
+	switch id.value.(type) {
+	case string:
+		return DocumentIDString
+	case json.Number:
+		return DocumentIDNumber
+	case bool:
+		return DocumentIDBoolean
+	case time.Time:
+		return DocumentIDDate
+	case UUID:
+		return DocumentIDUUID
+	case ObjectID:
+		return DocumentIDObjectID
+	}
+	return DocumentIDNone
+}
+
+// Value returns the value held by the DocumentID: a string, a json.Number, a bool, a
+// time.Time, a UUID, an ObjectID, or nil for the zero DocumentID.
+func (id DocumentID) Value() interface{} {
+	return id.value
+}
+
+// IsZero tells whether the DocumentID holds no value.
+func (id DocumentID) IsZero() bool {
+	return id.value == nil
+}
+
+// Equal tells whether two DocumentIDs hold the same value.
+func (id DocumentID) Equal(other DocumentID) bool {
+	if t, ok := id.value.(time.Time); ok {
+		o, ok := other.value.(time.Time)
+		return ok && t.Equal(o)
+	}
+	return id.value == other.value
+}
+
+// String returns a textual form of the value held by the DocumentID: strings are returned
+// as such, dates in RFC 3339 format, UUIDs and ObjectIDs in their canonical forms.
+func (id DocumentID) String() string {
+	switch v := id.value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return ""
+}
+
+// encodeTree implements treeEncoder.
+func (id DocumentID) encodeTree(e *encoder) (any, error) {
+	switch v := id.value.(type) {
+	case time.Time:
+		return e.encodeTime(v), nil
+	case treeEncoder:
+		return v.encodeTree(e)
+	}
+	return id.value, nil
+}
+
+// MarshalJSON implements json.Marshaler, producing the extended JSON form of the value
+// (e.g. the {"$uuid": ...} wrapper for UUIDs).
+func (id DocumentID) MarshalJSON() ([]byte, error) {
+	tree, err := id.encodeTree(&encoder{})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting any kind of document ID, either
+// plain or as an extended JSON wrapper. Numbers are preserved exactly.
+func (id *DocumentID) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case nil:
+		*id = DocumentID{}
+		return nil
+	case string, json.Number, bool:
+		*id = DocumentID{value: v}
+		return nil
+	case map[string]interface{}:
+		if decoded, ok := decodeWrapper(v); ok {
+			parsed, err := NewDocumentID(decoded)
+			if err != nil {
+				return err
+			}
+			*id = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid document ID: %s", data)
+}
+
+// generateDocumentID generates a document ID of the given type (see the DefaultIDType constants).
+func generateDocumentID(idType string) (DocumentID, error) {
+	var value interface{}
+	var err error
+	switch idType {
+	case DefaultIDTypeUUID:
+		value, err = NewUUIDv4()
+	case DefaultIDTypeUUIDv6:
+		value, err = NewUUIDv6()
+	case DefaultIDTypeUUIDv7:
+		value, err = NewUUIDv7()
+	case DefaultIDTypeObjectID:
+		value = NewObjectID()
+	default:
+		return DocumentID{}, fmt.Errorf("unsupported ID generation type %q", idType)
+	}
+	if err != nil {
+		return DocumentID{}, err
+	}
+	return DocumentID{value: value}, nil
+}
@@ -0,0 +1,182 @@
+package stragollum
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DataAPIErrorDescriptor describes a single entry of the "errors" array
+// found in a Data API response.
+type DataAPIErrorDescriptor struct {
+	ErrorCode string `json:"errorCode,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Family    string `json:"family,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	Title     string `json:"title,omitempty"`
+	ID        string `json:"id,omitempty"`
+}
+
+// String returns a compact human-readable form of the error descriptor.
+func (d DataAPIErrorDescriptor) String() string {
+	switch {
+	case d.ErrorCode != "" && d.Message != "":
+		return fmt.Sprintf("%s: %s", d.ErrorCode, d.Message)
+	case d.ErrorCode != "":
+		return d.ErrorCode
+	default:
+		return d.Message
+	}
+}
+
+// DataAPIResponseError is returned when the Data API answers (even with a 2xx status code)
+// with a non-empty top-level "errors" array.
+// Use errors.As to retrieve it from the error returned by any Database or Collection method.
+type DataAPIResponseError struct {
+	// Errors lists the error descriptors returned by the API, in order.
+	Errors []DataAPIErrorDescriptor
+	// Command is the request object that was sent to the API.
+	Command any
+	// RawResponse is the full (decoded) response body.
+	RawResponse map[string]any
+}
+
+// Error implements the error interface.
+func (e *DataAPIResponseError) Error() string {
+	if len(e.Errors) == 0 {
+		return "data API returned an error response"
+	}
+	descriptions := make([]string, len(e.Errors))
+	for i, d := range e.Errors {
+		descriptions[i] = d.String()
+	}
+	return fmt.Sprintf("data API returned errors: %s", strings.Join(descriptions, "; "))
+}
+
+// ErrorCodes returns the error codes of all errors in the response, in order.
+func (e *DataAPIResponseError) ErrorCodes() []string {
+	codes := make([]string, len(e.Errors))
+	for i, d := range e.Errors {
+		codes[i] = d.ErrorCode
+	}
+	return codes
+}
+
+// HasErrorCode reports whether any of the errors in the response carries the given error code.
+func (e *DataAPIResponseError) HasErrorCode(code string) bool {
+	for _, d := range e.Errors {
+		if d.ErrorCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// InsertManyFailure describes a document that could not be inserted by Collection.InsertMany.
+type InsertManyFailure struct {
+	// Index is the position of the document in the InsertMany input.
+	Index int
+	// Document is the document that failed, as sent: a document given a generated _id (see
+	// Collection.WithIDGeneration) is the json.RawMessage of the document with its _id.
+	Document any
+	// Err is the cause of the failure: a *DataAPIResponseError carrying the specific
+	// Data API error for the document, or the error that made its whole chunk fail.
+	Err error
+}
+
+// InsertManyError is returned by Collection.InsertMany when some documents could not be inserted.
+// Documents not attempted (after the first failure of an ordered insertion) are not listed.
+type InsertManyError struct {
+	// InsertedIDs lists the IDs of the documents that were inserted, in input order.
+	InsertedIDs []DocumentID
+	// Failures lists the documents that failed, in input order.
+	Failures []InsertManyFailure
+}
+
+// Error implements the error interface.
+func (e *InsertManyError) Error() string {
+	msg := fmt.Sprintf("insertMany: %d document(s) inserted, %d failed", len(e.InsertedIDs), len(e.Failures))
+	if len(e.Failures) > 0 && e.Failures[0].Err != nil {
+		msg += fmt.Sprintf(" (first failure at index %d: %v)", e.Failures[0].Index, e.Failures[0].Err)
+	}
+	return msg
+}
+
+// TableInsertManyError is returned by Table.InsertMany when some rows could not be inserted.
+// Rows not attempted (after the first failure of an ordered insertion) are not listed. A row
+// whose primary key, as returned by the Data API, could not be decoded is listed among the
+// failures with the decoding error, although it was inserted.
+type TableInsertManyError struct {
+	// InsertedKeys lists the primary keys of the rows that were inserted, in input order.
+	InsertedKeys []PrimaryKey
+	// Failures lists the rows that failed, in input order.
+	Failures []InsertManyFailure
+}
+
+// Error implements the error interface.
+func (e *TableInsertManyError) Error() string {
+	msg := fmt.Sprintf("insertMany: %d row(s) inserted, %d failed", len(e.InsertedKeys), len(e.Failures))
+	if len(e.Failures) > 0 && e.Failures[0].Err != nil {
+		msg += fmt.Sprintf(" (first failure at index %d: %v)", e.Failures[0].Index, e.Failures[0].Err)
+	}
+	return msg
+}
+
+// TooManyDocumentsToCountError is returned by Collection.CountDocuments when the exact
+// count cannot be given: either it exceeds the requested upper bound, or the API stopped
+// counting at its own limit.
+type TooManyDocumentsToCountError struct {
+	// Limit is the count at which counting stopped: the upper bound requested by the
+	// caller, or the count reported by the API when its own limit was hit.
+	Limit int
+	// HitServerLimit tells whether the API's own limit was hit (rather than the upper bound).
+	HitServerLimit bool
+}
+
+// Error implements the error interface.
+func (e *TooManyDocumentsToCountError) Error() string {
+	if e.HitServerLimit {
+		return fmt.Sprintf("too many documents to count: the server limit of %d was reached", e.Limit)
+	}
+	return fmt.Sprintf("too many documents to count: more than the requested upper bound of %d", e.Limit)
+}
+
+// CollectionDefinitionDifference is a setting of a collection definition differing between
+// the requested and the existing definition.
+type CollectionDefinitionDifference struct {
+	// Field is the path of the setting, e.g. "vector.dimension".
+	Field string
+	// Requested and Existing are the values of the setting (nil if absent).
+	Requested any
+	Existing  any
+}
+
+// CollectionDefinitionMismatchError is returned by Database.CreateCollection, when checking
+// for an existing collection, if the collection exists with a different definition.
+type CollectionDefinitionMismatchError struct {
+	// Name is the name of the collection.
+	Name string
+	// Differences lists the settings that differ.
+	Differences []CollectionDefinitionDifference
+}
+
+// Error implements the error interface.
+func (e *CollectionDefinitionMismatchError) Error() string {
+	details := make([]string, len(e.Differences))
+	for i, d := range e.Differences {
+		details[i] = fmt.Sprintf("%s (requested %s, existing %s)", d.Field, describeSetting(d.Requested), describeSetting(d.Existing))
+	}
+	return fmt.Sprintf("collection %q exists with a different definition: %s", e.Name, strings.Join(details, "; "))
+}
+
+// describeSetting formats a setting of a collection definition for an error message.
+func describeSetting(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	if string(encoded) == "null" {
+		return "none"
+	}
+	return string(encoded)
+}
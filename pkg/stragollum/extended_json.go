@@ -0,0 +1,193 @@
+package stragollum
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// The Data API extends JSON with a few wrapper objects for values JSON lacks:
+//
+//	{"$date": <milliseconds since the Unix epoch>}
+//	{"$uuid": "<canonical UUID>"}
+//	{"$objectId": "<hex ObjectId>"}
+//	{"$binary": "<base64>"}  (for vectors, see DataAPIVector)
+//
+// In the requests, time.Time, UUID, ObjectID and DataAPIVector values are serialized
+// as such. In the responses, the wrappers are decoded back into those types when
+// documents are decoded into untyped maps, and into the corresponding struct fields
+// (time.Time fields included) when decoded into structs.
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// dateMarker reveals, in a raw document, a possible date wrapper.
+var dateMarker = []byte(`"$date"`)
+
+// encodeTime converts a time into the $date wrapper (an RFC 3339 string for tables).
+func (e *encoder) encodeTime(t time.Time) any {
+	if e.tables {
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	return map[string]any{"$date": t.UnixMilli()}
+}
+
+// decodeExtendedValue converts the wrappers found in an untyped JSON tree into the
+// corresponding Go values. Maps and slices are modified in place.
+func decodeExtendedValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if decoded, ok := decodeWrapper(v); ok {
+			return decoded
+		}
+		for key, item := range v {
+			if key == VectorField {
+				if vector, ok := decodeVectorWrapper(item); ok {
+					v[key] = vector
+					continue
+				}
+			}
+			v[key] = decodeExtendedValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = decodeExtendedValue(item)
+		}
+	}
+	return value
+}
+
+// decodeWrapper converts a $date, $uuid or $objectId wrapper, reporting false if object is not one.
+func decodeWrapper(object map[string]interface{}) (interface{}, bool) {
+	if len(object) != 1 {
+		return nil, false
+	}
+	for key, value := range object {
+		switch key {
+		case "$date":
+			if ms, ok := jsonInt64(value); ok {
+				return time.UnixMilli(ms).UTC(), true
+			}
+		case "$uuid":
+			if s, ok := value.(string); ok {
+				if u, err := ParseUUID(s); err == nil {
+					return u, true
+				}
+			}
+		case "$objectId":
+			if s, ok := value.(string); ok {
+				if id, err := ParseObjectID(s); err == nil {
+					return id, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// decodeVectorWrapper converts a binary-encoded vector, reporting false if value is not one.
+func decodeVectorWrapper(value interface{}) (DataAPIVector, bool) {
+	object, ok := value.(map[string]interface{})
+	if !ok || len(object) != 1 {
+		return nil, false
+	}
+	encoded, ok := object["$binary"].(string)
+	if !ok {
+		return nil, false
+	}
+	vector, err := decodeBinaryVector(encoded)
+	if err != nil {
+		return nil, false
+	}
+	return vector, true
+}
+
+// jsonInt64 reads an integer from a decoded JSON number (float64 or json.Number).
+func jsonInt64(value interface{}) (int64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return int64(n), true
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return i, true
+		}
+		if f, err := n.Float64(); err == nil {
+			return int64(f), true
+		}
+	}
+	return 0, false
+}
+
+// normalizeDates rewrites the $date wrappers of a raw document decoded into a value of type t
+// as RFC 3339 strings, which is how time.Time fields decode. Only the wrappers landing on
+// time.Time values are rewritten: the others (e.g. under interface{} fields) are kept as they
+// are. Numbers are preserved exactly.
+func normalizeDates(raw []byte, t reflect.Type) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return json.Marshal(normalizeDateValue(tree, t))
+}
+
+func normalizeDateValue(value interface{}, t reflect.Type) interface{} {
+	if t == nil {
+		return value
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		if v, ok := value.(map[string]interface{}); ok && len(v) == 1 {
+			if millis, ok := jsonInt64(v["$date"]); ok {
+				return time.UnixMilli(millis).UTC().Format(time.RFC3339Nano)
+			}
+		}
+		return value
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Map:
+			for key, item := range v {
+				v[key] = normalizeDateValue(item, t.Elem())
+			}
+		case reflect.Struct:
+			fields := structFields(t)
+			for key, item := range v {
+				if f, ok := fieldForKey(fields, key); ok {
+					v[key] = normalizeDateValue(item, t.FieldByIndex(f.index).Type)
+				}
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				v[i] = normalizeDateValue(item, t.Elem())
+			}
+		}
+	}
+	return value
+}
+
+// fieldForKey returns the struct field a document key decodes into, matching names the way
+// encoding/json does (exact match first, then case-insensitive).
+func fieldForKey(fields []structField, key string) (structField, bool) {
+	for _, f := range fields {
+		if f.name == key || f.jsonName == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.jsonName, key) {
+			return f, true
+		}
+	}
+	return structField{}, false
+}
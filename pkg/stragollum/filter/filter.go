@@ -0,0 +1,244 @@
+// Package filter provides constructors for Data API filters, e.g.
+//
+//	filter.And(
+//		filter.Eq("status", "active"),
+//		filter.Or(filter.Gte("age", 18), filter.Exists("guardian")),
+//	)
+//
+// which marshals to
+//
+//	{"$and": [{"status": {"$eq": "active"}}, {"$or": [{"age": {"$gte": 18}}, {"guardian": {"$exists": true}}]}]}
+//
+// A Filter is a plain map, so it can be passed wherever the stragollum package accepts a
+// filter, and raw maps (map[string]any) can be used wherever a Filter is expected.
+package filter
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Filter is a Data API filter.
+type Filter map[string]any
+
+// Field operators.
+const (
+	OpEq     = "$eq"
+	OpNe     = "$ne"
+	OpIn     = "$in"
+	OpNin    = "$nin"
+	OpGt     = "$gt"
+	OpGte    = "$gte"
+	OpLt     = "$lt"
+	OpLte    = "$lte"
+	OpExists = "$exists"
+	OpAll    = "$all"
+	OpSize   = "$size"
+	OpMatch  = "$match"
+)
+
+// Logical operators.
+const (
+	OpAnd = "$and"
+	OpOr  = "$or"
+	OpNot = "$not"
+)
+
+// LexicalField is the field holding the text indexed for lexical search.
+const LexicalField = "$lexical"
+
+// fieldCondition builds the filter {field: {operator: value}}.
+func fieldCondition(field string, operator string, value any) Filter {
+	return Filter{field: map[string]any{operator: value}}
+}
+
+// Eq matches the documents where field equals value.
+func Eq(field string, value any) Filter {
+	return fieldCondition(field, OpEq, value)
+}
+
+// Ne matches the documents where field does not equal value.
+func Ne(field string, value any) Filter {
+	return fieldCondition(field, OpNe, value)
+}
+
+// In matches the documents where field equals any of values.
+// Each argument is one value, slices included: spread a []any to use its elements as the
+// values, e.g. In("tag", tags...).
+func In(field string, values ...any) Filter {
+	return fieldCondition(field, OpIn, nonNil(values))
+}
+
+// Nin matches the documents where field equals none of values.
+// Each argument is one value, slices included (see In).
+func Nin(field string, values ...any) Filter {
+	return fieldCondition(field, OpNin, nonNil(values))
+}
+
+// Gt matches the documents where field is greater than value.
+func Gt(field string, value any) Filter {
+	return fieldCondition(field, OpGt, value)
+}
+
+// Gte matches the documents where field is greater than or equal to value.
+func Gte(field string, value any) Filter {
+	return fieldCondition(field, OpGte, value)
+}
+
+// Lt matches the documents where field is less than value.
+func Lt(field string, value any) Filter {
+	return fieldCondition(field, OpLt, value)
+}
+
+// Lte matches the documents where field is less than or equal to value.
+func Lte(field string, value any) Filter {
+	return fieldCondition(field, OpLte, value)
+}
+
+// Exists matches the documents having field.
+func Exists(field string) Filter {
+	return fieldCondition(field, OpExists, true)
+}
+
+// All matches the documents where field is an array containing all of values.
+// Each argument is one value, slices included (see In).
+func All(field string, values ...any) Filter {
+	return fieldCondition(field, OpAll, nonNil(values))
+}
+
+// Size matches the documents where field is an array with the given number of elements.
+func Size(field string, size int) Filter {
+	return fieldCondition(field, OpSize, size)
+}
+
+// Match matches the documents whose lexical field ($lexical) matches the given query
+// (the collection must have lexical search enabled).
+func Match(query string) Filter {
+	return fieldCondition(LexicalField, OpMatch, query)
+}
+
+// And matches the documents matching all of filters.
+func And(filters ...Filter) Filter {
+	return Filter{OpAnd: nonNilFilters(filters)}
+}
+
+// Or matches the documents matching any of filters.
+func Or(filters ...Filter) Filter {
+	return Filter{OpOr: nonNilFilters(filters)}
+}
+
+// Not matches the documents not matching f.
+func Not(f Filter) Filter {
+	if f == nil {
+		f = Filter{}
+	}
+	return Filter{OpNot: f}
+}
+
+// Merge combines filters into a single one, requiring all of their conditions: e.g.
+// Merge(Gte("age", 18), Lt("age", 65)) is {"age": {"$gte": 18, "$lt": 65}}.
+// When a field appears in several filters, their operator conditions are combined if they
+// have no operator in common; otherwise (same operator, equality values, $or and $not
+// conditions) the later condition is added to a $and list, so that no condition is lost.
+// The $and lists of the filters are concatenated.
+func Merge(filters ...Filter) Filter {
+	merged := Filter{}
+	var and []any
+	for _, f := range filters {
+		fields := make([]string, 0, len(f))
+		for field := range f {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			condition := f[field]
+			if field == OpAnd {
+				and = append(and, listItems(condition)...)
+				continue
+			}
+			previous, exists := merged[field]
+			if !exists {
+				merged[field] = condition
+				continue
+			}
+			if combined, ok := combineConditions(field, previous, condition); ok {
+				merged[field] = combined
+				continue
+			}
+			and = append(and, Filter{field: condition})
+		}
+	}
+	if and != nil {
+		merged[OpAnd] = and
+	}
+	return merged
+}
+
+// combineConditions combines two operator conditions on the same field, reporting false
+// if they cannot be combined without losing one of them.
+func combineConditions(field string, previous any, current any) (map[string]any, bool) {
+	if field == OpOr || field == OpNot {
+		return nil, false
+	}
+	previousOps, okPrevious := previous.(map[string]any)
+	currentOps, okCurrent := current.(map[string]any)
+	if !okPrevious || !okCurrent || !isOperatorCondition(previousOps) || !isOperatorCondition(currentOps) {
+		return nil, false
+	}
+	combined := make(map[string]any, len(previousOps)+len(currentOps))
+	for operator, value := range previousOps {
+		combined[operator] = value
+	}
+	for operator, value := range currentOps {
+		if _, clash := combined[operator]; clash {
+			return nil, false
+		}
+		combined[operator] = value
+	}
+	return combined, true
+}
+
+// listItems returns the items of a $and list (any kind of slice), or the value itself if
+// it is not a list.
+func listItems(list any) []any {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []any{list}
+	}
+	items := make([]any, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items
+}
+
+// isOperatorCondition tells whether a field condition is made of operators only
+// (as opposed to an equality match against an object).
+func isOperatorCondition(condition map[string]any) bool {
+	for key := range condition {
+		if len(key) == 0 || key[0] != '$' {
+			return false
+		}
+	}
+	return len(condition) > 0
+}
+
+// nonNil makes sure a list of values marshals to an array (not null).
+func nonNil(values []any) []any {
+	if values == nil {
+		return []any{}
+	}
+	return values
+}
+
+// nonNilFilters makes sure a list of filters marshals to an array of objects.
+func nonNilFilters(filters []Filter) []Filter {
+	result := make([]Filter, len(filters))
+	for i, f := range filters {
+		if f == nil {
+			f = Filter{}
+		}
+		result[i] = f
+	}
+	return result
+}
@@ -0,0 +1,257 @@
+package stragollum
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// NumberDecoding is the representation of the numbers found in the documents read into
+// untyped values (maps and interface{} fields). Typed struct fields decode according to
+// their type: int64, *big.Int and Decimal fields are always exact. Table rows are decoded
+// according to the schema of the table instead.
+type NumberDecoding string
+
+// Constants for NumberDecoding type
+const (
+	// NumberDecodingFloat64 decodes numbers as float64, as encoding/json does: integers
+	// beyond 2^53 and decimals with many digits lose precision.
+	NumberDecodingFloat64 NumberDecoding = "float64"
+	// NumberDecodingJSONNumber decodes numbers as json.Number, keeping their text.
+	NumberDecodingJSONNumber NumberDecoding = "jsonNumber"
+	// NumberDecodingLossless decodes integers as int64, or as *big.Int if they do not fit,
+	// and other numbers as Decimal. The components of $vector arrays, float32 values on the
+	// Data API side, are decoded as float64. interface{} fields of structs get json.Number.
+	NumberDecodingLossless NumberDecoding = "lossless"
+)
+
+var bigFloatType = reflect.TypeOf(big.Float{})
+
+// Decimal is an arbitrary-precision decimal number, for values float64 cannot represent
+// exactly (such as prices). Its value is unscaled × 10^-scale; the zero Decimal is 0.
+// It is serialized as a JSON number with all its digits.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// decimalPattern matches the textual representations accepted by ParseDecimal.
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// NewDecimal creates the Decimal unscaled × 10^-scale, e.g. NewDecimal(big.NewInt(1999), 2)
+// for 19.99.
+func NewDecimal(unscaled *big.Int, scale int32) Decimal {
+	return Decimal{unscaled: new(big.Int).Set(unscaled), scale: scale}
+}
+
+// ParseDecimal parses a decimal number, in the notation of JSON numbers (e.g. "19.99",
+// "-1.5e-3"). Leading zeros and a leading "+" are accepted as well.
+func ParseDecimal(s string) (Decimal, error) {
+	if !decimalPattern.MatchString(s) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	mantissa, exponent, _ := strings.Cut(strings.ToLower(s), "e")
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	unscaled, ok := new(big.Int).SetString(integer+fraction, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	scale := int64(len(fraction))
+	if exponent != "" {
+		exp, ok := new(big.Int).SetString(exponent, 10)
+		if !ok || !exp.IsInt64() {
+			return Decimal{}, fmt.Errorf("decimal exponent out of range in %q", s)
+		}
+		scale -= exp.Int64()
+	}
+	if scale < math.MinInt32 || scale > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("decimal exponent out of range in %q", s)
+	}
+	return Decimal{unscaled: unscaled, scale: int32(scale)}, nil
+}
+
+// Unscaled returns the unscaled value of the Decimal.
+func (d Decimal) Unscaled() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(d.unscaled)
+}
+
+// Scale returns the scale of the Decimal (the number of digits after the decimal point).
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Rat returns the exact value of the Decimal as a rational number.
+func (d Decimal) Rat() *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(absInt32(d.scale))), nil)
+	if d.scale >= 0 {
+		return new(big.Rat).SetFrac(d.Unscaled(), scale)
+	}
+	return new(big.Rat).SetInt(scale.Mul(scale, d.Unscaled()))
+}
+
+// Float64 returns the float64 value nearest to the Decimal.
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// Cmp compares two Decimals by value (1.50 equals 1.5), returning -1, 0 or +1.
+func (d Decimal) Cmp(other Decimal) int {
+	return d.Rat().Cmp(other.Rat())
+}
+
+// String returns the Decimal in plain notation (without exponent), e.g. "19.99".
+func (d Decimal) String() string {
+	unscaled := d.Unscaled()
+	sign := ""
+	if unscaled.Sign() < 0 {
+		sign = "-"
+		unscaled.Neg(unscaled)
+	}
+	digits := unscaled.String()
+	if d.scale <= 0 {
+		if unscaled.Sign() == 0 {
+			return "0"
+		}
+		return sign + digits + strings.Repeat("0", int(-d.scale))
+	}
+	scale := int(d.scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// MarshalJSON implements json.Marshaler, as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting a JSON number or a string holding one.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	text := string(bytes.TrimSpace(data))
+	if text == "null" {
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	decimal, err := ParseDecimal(text)
+	if err != nil {
+		return err
+	}
+	*d = decimal
+	return nil
+}
+
+func absInt32(v int32) int64 {
+	if v < 0 {
+		return -int64(v)
+	}
+	return int64(v)
+}
+
+// encodeBigFloat converts a big.Float into a JSON number, with as many digits as needed to
+// represent it exactly at its precision.
+func encodeBigFloat(f *big.Float) (any, error) {
+	if f.IsInf() {
+		return nil, fmt.Errorf("unsupported value for JSON encoding: %s", f.String())
+	}
+	return json.Number(f.Text('g', -1)), nil
+}
+
+// decodeNumbers decodes JSON into v, with numbers as json.Number.
+func decodeNumbers(raw []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// unmarshalNumbers decodes JSON into v, with the numbers of untyped values represented
+// according to numbers (only float64 or json.Number: see convertNumbers).
+func unmarshalNumbers(raw []byte, v interface{}, numbers NumberDecoding) error {
+	if numbers == NumberDecodingJSONNumber || numbers == NumberDecodingLossless {
+		return decodeNumbers(raw, v)
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// convertNumbers converts the json.Number values of an untyped JSON tree for the
+// NumberDecodingLossless mode (see losslessNumber). Maps and slices are modified in place.
+func convertNumbers(value interface{}, numbers NumberDecoding) interface{} {
+	if numbers != NumberDecodingLossless {
+		return value
+	}
+	switch v := value.(type) {
+	case json.Number:
+		return losslessNumber(v)
+	case map[string]interface{}:
+		for key, item := range v {
+			if components, ok := item.([]interface{}); ok && key == VectorField {
+				for i, component := range components {
+					if n, ok := component.(json.Number); ok {
+						if f, err := n.Float64(); err == nil {
+							components[i] = f
+						}
+					}
+				}
+				continue
+			}
+			v[key] = convertNumbers(item, numbers)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertNumbers(item, numbers)
+		}
+	}
+	return value
+}
+
+// losslessNumber converts a json.Number into an int64, a *big.Int or a Decimal.
+func losslessNumber(n json.Number) interface{} {
+	text := n.String()
+	if !strings.ContainsAny(text, ".eE") {
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		if i, ok := new(big.Int).SetString(text, 10); ok {
+			return i
+		}
+	}
+	if d, err := ParseDecimal(text); err == nil {
+		return d
+	}
+	return n
+}
+
+// numberRat returns the exact value of a decoded number, or nil if value is not a number.
+func numberRat(value interface{}) *big.Rat {
+	switch v := value.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+		return new(big.Rat).SetFloat64(v)
+	case int64:
+		return new(big.Rat).SetInt64(v)
+	case *big.Int:
+		return new(big.Rat).SetInt(v)
+	case Decimal:
+		return v.Rat()
+	case json.Number:
+		if d, err := ParseDecimal(v.String()); err == nil {
+			return d.Rat()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package stragollum
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ObjectID is a (MongoDB-style) ObjectId, stored by the Data API as {"$objectId": "<hex>"}:
+// values of this type are serialized as such, and such wrappers are decoded back into
+// ObjectID values.
+type ObjectID [12]byte
+
+// objectIDProcessUnique is the random part of the ObjectIDs generated by this process.
+var objectIDProcessUnique = func() [5]byte {
+	var b [5]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Errorf("failed to initialize the ObjectID generator: %w", err))
+	}
+	return b
+}()
+
+// objectIDCounter is the counter part of the ObjectIDs generated by this process.
+var objectIDCounter = func() uint32 {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Errorf("failed to initialize the ObjectID generator: %w", err))
+	}
+	return binary.BigEndian.Uint32(b[:])
+}()
+
+// NewObjectID generates a new ObjectID: a timestamp in seconds, a random value unique to
+// the process and an incrementing counter.
+func NewObjectID() ObjectID {
+	var id ObjectID
+	binary.BigEndian.PutUint32(id[0:4], uint32(time.Now().Unix()))
+	copy(id[4:9], objectIDProcessUnique[:])
+	counter := atomic.AddUint32(&objectIDCounter, 1)
+	id[9], id[10], id[11] = byte(counter>>16), byte(counter>>8), byte(counter)
+	return id
+}
+
+// ParseObjectID parses an ObjectID in its hexadecimal form (24 digits).
+func ParseObjectID(s string) (ObjectID, error) {
+	var id ObjectID
+	if len(s) != 24 {
+		return id, fmt.Errorf("invalid ObjectID %q", s)
+	}
+	if _, err := hex.Decode(id[:], []byte(s)); err != nil {
+		return ObjectID{}, fmt.Errorf("invalid ObjectID %q: %w", s, err)
+	}
+	return id, nil
+}
+
+// Hex returns the hexadecimal form of the ObjectID.
+func (id ObjectID) Hex() string {
+	return hex.EncodeToString(id[:])
+}
+
+// String returns the hexadecimal form of the ObjectID.
+func (id ObjectID) String() string {
+	return id.Hex()
+}
+
+// Timestamp returns the generation time encoded in the ObjectID.
+func (id ObjectID) Timestamp() time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(id[0:4])), 0).UTC()
+}
+
+// encodeTree implements treeEncoder.
+func (id ObjectID) encodeTree(e *encoder) (any, error) {
+	return map[string]any{"$objectId": id.Hex()}, nil
+}
+
+// MarshalJSON implements json.Marshaler, producing the {"$objectId": ...} wrapper.
+func (id ObjectID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"$objectId": id.Hex()})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting both the {"$objectId": ...} wrapper and a plain string.
+func (id *ObjectID) UnmarshalJSON(data []byte) error {
+	s, err := unwrapExtendedString(data, "$objectId")
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseObjectID(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
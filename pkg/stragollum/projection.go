@@ -0,0 +1,136 @@
+package stragollum
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AllFields is the wildcard projection field, standing for all the fields of a document.
+const AllFields = "*"
+
+// Projection is a projection specification, built by chaining the methods below, e.g.
+//
+//	stragollum.NewProjection().Include("title", "author").Slice("comments", 3)
+//
+// As required by the Data API, a projection either includes or excludes fields: mixing
+// the two makes it invalid (except for excluding _id), as does combining the wildcard
+// with other fields. An invalid Projection makes the request fail before being sent.
+// A *Projection can be used as the Projection of the find options.
+type Projection struct {
+	fields orderedObject
+	// polarity is true for an inclusion projection, false for an exclusion one
+	polarity *bool
+	err      error
+}
+
+// NewProjection creates an empty Projection.
+func NewProjection() *Projection {
+	return &Projection{}
+}
+
+// add appends a projection field, checking it against the fields already present.
+// include is the polarity of the field, nil for fields (like $slice) compatible with both.
+func (p *Projection) add(field string, value any, include *bool) *Projection {
+	if p.err != nil {
+		return p
+	}
+	if field == "" {
+		p.err = fmt.Errorf("invalid projection: empty field name")
+		return p
+	}
+	for _, existing := range p.fields {
+		switch {
+		case existing.key == field:
+			p.err = fmt.Errorf("invalid projection: field %q given more than once", field)
+		case existing.key == AllFields || field == AllFields:
+			p.err = fmt.Errorf("invalid projection: the %q wildcard cannot be combined with other fields", AllFields)
+		}
+		if p.err != nil {
+			return p
+		}
+	}
+	// Excluding _id is allowed in an inclusion projection, and special ($-prefixed)
+	// fields do not affect the polarity
+	if include != nil && field != IDField && !strings.HasPrefix(field, "$") {
+		if p.polarity != nil && *p.polarity != *include {
+			p.err = fmt.Errorf("invalid projection: cannot mix inclusion and exclusion (field %q)", field)
+			return p
+		}
+		p.polarity = include
+	}
+	p.fields = append(p.fields, orderedField{key: field, value: value})
+	return p
+}
+
+// Include includes the given fields (and excludes all others).
+func (p *Projection) Include(fields ...string) *Projection {
+	include := true
+	for _, field := range fields {
+		p.add(field, true, &include)
+	}
+	return p
+}
+
+// Exclude excludes the given fields (and includes all others).
+func (p *Projection) Exclude(fields ...string) *Projection {
+	include := false
+	for _, field := range fields {
+		p.add(field, false, &include)
+	}
+	return p
+}
+
+// IncludeAll includes all fields (the "*" wildcard). It cannot be combined with other fields.
+func (p *Projection) IncludeAll() *Projection {
+	return p.add(AllFields, true, nil)
+}
+
+// ExcludeAll excludes all fields (the "*" wildcard), e.g. to only get the similarity of the
+// documents returned by a vector search. It cannot be combined with other fields.
+func (p *Projection) ExcludeAll() *Projection {
+	return p.add(AllFields, false, nil)
+}
+
+// Slice includes the first count elements of an array field (the last -count ones if count
+// is negative).
+func (p *Projection) Slice(field string, count int) *Projection {
+	return p.add(field, map[string]any{"$slice": count}, nil)
+}
+
+// SliceRange includes limit elements of an array field, starting at skip (counting from the
+// end if skip is negative).
+func (p *Projection) SliceRange(field string, skip int, limit int) *Projection {
+	if limit <= 0 && p.err == nil {
+		p.err = fmt.Errorf("invalid projection: $slice limit must be positive, got %d", limit)
+		return p
+	}
+	return p.add(field, map[string]any{"$slice": []int{skip, limit}}, nil)
+}
+
+// IncludeSimilarity includes the similarity score ($similarity) of the documents returned
+// by a vector search.
+func (p *Projection) IncludeSimilarity() *Projection {
+	return p.add(SimilarityField, true, nil)
+}
+
+// Err returns the error making the Projection invalid, if any.
+func (p Projection) Err() error {
+	return p.err
+}
+
+// encodeTree implements treeEncoder.
+func (p Projection) encodeTree(e *encoder) (any, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.fields, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p Projection) MarshalJSON() ([]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return json.Marshal(p.fields)
+}
@@ -0,0 +1,135 @@
+package stragollum
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Sort directions.
+const (
+	SortAscending  = 1
+	SortDescending = -1
+)
+
+// Sort is a sort specification, built by chaining the methods below, e.g.
+//
+//	stragollum.NewSort().Descending("priority").Ascending("createdAt")
+//
+// Unlike a map, a Sort keeps the order of its fields, which is the order in which they
+// are applied. Vector, vectorize, lexical and hybrid sorts exclude any other sort field.
+// An invalid Sort makes the request fail before being sent. A *Sort can be used as the
+// Sort of the find, update and delete options.
+type Sort struct {
+	fields orderedObject
+	err    error
+}
+
+// NewSort creates an empty Sort.
+func NewSort() *Sort {
+	return &Sort{}
+}
+
+// add appends a sort field, checking it against the fields already present.
+func (s *Sort) add(field string, value any) *Sort {
+	if s.err != nil {
+		return s
+	}
+	if field == "" {
+		s.err = fmt.Errorf("invalid sort: empty field name")
+		return s
+	}
+	for _, existing := range s.fields {
+		switch {
+		case existing.key == field:
+			s.err = fmt.Errorf("invalid sort: field %q given more than once", field)
+		case isSpecialSortField(existing.key) || isSpecialSortField(field):
+			s.err = fmt.Errorf("invalid sort: %q cannot be combined with %q", field, existing.key)
+		}
+		if s.err != nil {
+			return s
+		}
+	}
+	s.fields = append(s.fields, orderedField{key: field, value: value})
+	return s
+}
+
+// isSpecialSortField tells whether a sort field is a vector, lexical or hybrid sort,
+// which must be the only field of its sort.
+func isSpecialSortField(field string) bool {
+	switch field {
+	case VectorField, VectorizeField, LexicalField, HybridField:
+		return true
+	}
+	return false
+}
+
+// Ascending sorts by field, in ascending order.
+func (s *Sort) Ascending(field string) *Sort {
+	return s.add(field, SortAscending)
+}
+
+// Descending sorts by field, in descending order.
+func (s *Sort) Descending(field string) *Sort {
+	return s.add(field, SortDescending)
+}
+
+// Vector sorts by similarity to the given vector (vector search).
+func (s *Sort) Vector(vector []float32) *Sort {
+	return s.add(VectorField, DataAPIVector(vector))
+}
+
+// Vectorize sorts by similarity to the vector computed from the given text (vector search).
+func (s *Sort) Vectorize(text string) *Sort {
+	return s.add(VectorizeField, text)
+}
+
+// Lexical sorts by relevance to the given query (lexical search).
+func (s *Sort) Lexical(query string) *Sort {
+	return s.add(LexicalField, query)
+}
+
+// Hybrid sorts by the given text, used for both the vector and the lexical search (hybrid
+// search, see Collection.FindAndRerank).
+func (s *Sort) Hybrid(text string) *Sort {
+	return s.add(HybridField, text)
+}
+
+// HybridVectorize is like Hybrid, with separate texts for the vector and the lexical search.
+func (s *Sort) HybridVectorize(vectorize string, lexical string) *Sort {
+	return s.add(HybridField, map[string]any{VectorizeField: vectorize, LexicalField: lexical})
+}
+
+// HybridVector is like Hybrid, with a vector for the vector search.
+func (s *Sort) HybridVector(vector []float32, lexical string) *Sort {
+	return s.add(HybridField, map[string]any{VectorField: DataAPIVector(vector), LexicalField: lexical})
+}
+
+// Err returns the error making the Sort invalid, if any.
+func (s Sort) Err() error {
+	return s.err
+}
+
+// encodeTree implements treeEncoder.
+func (s Sort) encodeTree(e *encoder) (any, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	object := make(orderedObject, len(s.fields))
+	for i, field := range s.fields {
+		value, err := e.encodeValue(field.value)
+		if err != nil {
+			return nil, err
+		}
+		object[i] = orderedField{key: field.key, value: value}
+	}
+	return object, nil
+}
+
+// MarshalJSON implements json.Marshaler, with the default vector encoding.
+func (s Sort) MarshalJSON() ([]byte, error) {
+	tree, err := s.encodeTree(&encoder{vectorEncoding: DefaultVectorEncoding})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
@@ -0,0 +1,415 @@
+package stragollum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Table represents a connection to a specific table in the database.
+// The number decoding setting of the client and the database (see NumberDecoding) does not
+// apply to tables: rows are decoded according to the schema of the table, without loss,
+// and the numbers landing in the interface{} fields of structs are json.Number.
+type Table struct {
+	apiEndpoint string
+	name        string
+	token       *string
+	keyspace    string
+	commander   *DataAPICommander
+	// database is the Database the Table was obtained from
+	database *Database
+}
+
+// Name returns the name of the Table.
+func (t *Table) Name() string {
+	return t.name
+}
+
+// Keyspace returns the keyspace of the Table.
+func (t *Table) Keyspace() string {
+	return t.keyspace
+}
+
+// TimeoutOptions returns the Table's default timeouts.
+func (t *Table) TimeoutOptions() TimeoutOptions {
+	return t.commander.TimeoutOptions()
+}
+
+// WithTimeoutOptions sets the Table's default timeouts.
+func (t *Table) WithTimeoutOptions(timeoutOptions TimeoutOptions) *Table {
+	t.commander.WithTimeoutOptions(timeoutOptions)
+	return t
+}
+
+// VectorEncoding returns the Table's serialization of DataAPIVector values.
+func (t *Table) VectorEncoding() VectorEncoding {
+	return t.commander.VectorEncoding()
+}
+
+// WithVectorEncoding sets the Table's serialization of DataAPIVector values in the requests.
+func (t *Table) WithVectorEncoding(vectorEncoding VectorEncoding) *Table {
+	t.commander.WithVectorEncoding(vectorEncoding)
+	return t
+}
+
+// ErrTableUpdateOperator is returned by Table.UpdateOne for updates with operators other
+// than $set and $unset, which tables do not support.
+var ErrTableUpdateOperator = errors.New("table updates only support the $set and $unset operators")
+
+// InsertOne inserts a single row into the table, returning its primary key.
+// It takes any Go type that can be marshalled to a JSON object as the row.
+func (t *Table) InsertOne(row interface{}) (PrimaryKey, error) {
+	return t.InsertOneContext(context.Background(), row)
+}
+
+// InsertOneContext is like InsertOne, with a context bounding the operation.
+func (t *Table) InsertOneContext(ctx context.Context, row interface{}) (PrimaryKey, error) {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	requestPayload := struct {
+		InsertOne struct {
+			Document interface{} `json:"document"`
+		} `json:"insertOne"`
+	}{}
+	requestPayload.InsertOne.Document = row
+
+	// The API returns: {"status": {"primaryKeySchema": {...}, "insertedIds": [[<key values>]]}}
+	var response struct {
+		Status struct {
+			PrimaryKeySchema tableSchema       `json:"primaryKeySchema"`
+			InsertedIds      []json.RawMessage `json:"insertedIds"`
+		} `json:"status"`
+	}
+	if err := t.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+		return PrimaryKey{}, err
+	}
+	if len(response.Status.InsertedIds) == 0 {
+		return PrimaryKey{}, fmt.Errorf("no primary key returned after insertion")
+	}
+	key, err := response.Status.PrimaryKeySchema.primaryKey(response.Status.InsertedIds[0])
+	if err != nil {
+		return PrimaryKey{}, fmt.Errorf("failed to decode the inserted primary key: %w", err)
+	}
+	return key, nil
+}
+
+// InsertMany inserts the given rows into the table, in chunks as for Collection.InsertMany
+// (see InsertManyOptions; options may be nil). It returns the primary keys of the inserted
+// rows, in input order. If some rows could not be inserted, the returned error is a
+// *TableInsertManyError detailing what was inserted and what failed.
+func (t *Table) InsertMany(rows []interface{}, options *InsertManyOptions) ([]PrimaryKey, error) {
+	return t.InsertManyContext(context.Background(), rows, options)
+}
+
+// InsertManyContext is like InsertMany, with a context bounding the whole operation.
+func (t *Table) InsertManyContext(ctx context.Context, rows []interface{}, options *InsertManyOptions) ([]PrimaryKey, error) {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	insertedKeys, failures := runInsertMany(ctx, rows, options, t.insertManyChunk)
+	if len(failures) > 0 {
+		return insertedKeys, &TableInsertManyError{InsertedKeys: insertedKeys, Failures: failures}
+	}
+	return insertedKeys, nil
+}
+
+// insertManyChunk inserts a single chunk of rows with one insertMany command.
+func (t *Table) insertManyChunk(ctx context.Context, chunk insertManyChunk, ordered bool) insertManyChunkResult[PrimaryKey] {
+	requestPayload := struct {
+		InsertMany struct {
+			Documents []interface{} `json:"documents"`
+			Options   struct {
+				Ordered                 bool `json:"ordered"`
+				ReturnDocumentResponses bool `json:"returnDocumentResponses"`
+			} `json:"options"`
+		} `json:"insertMany"`
+	}{}
+	requestPayload.InsertMany.Documents = chunk.documents
+	requestPayload.InsertMany.Options.Ordered = ordered
+	requestPayload.InsertMany.Options.ReturnDocumentResponses = true
+
+	// The API returns one entry per input row, in order, the primary keys as arrays of
+	// values described by "primaryKeySchema":
+	// {"status": {"primaryKeySchema": {...}, "documentResponses": [{"_id": [<values>], "status": "OK|ERROR|SKIPPED", "errorsIdx": <n>}]}}
+	var response struct {
+		Status struct {
+			PrimaryKeySchema  tableSchema `json:"primaryKeySchema"`
+			DocumentResponses []struct {
+				ID        json.RawMessage `json:"_id"`
+				Status    string          `json:"status"`
+				ErrorsIdx *int            `json:"errorsIdx"`
+			} `json:"documentResponses"`
+		} `json:"status"`
+	}
+
+	var result insertManyChunkResult[PrimaryKey]
+	err := t.commander.RequestContext(ctx, requestPayload, &response)
+
+	var apiErr *DataAPIResponseError
+	if err == nil && len(response.Status.DocumentResponses) != len(chunk.documents) {
+		err = fmt.Errorf("unexpected response: expected %d document responses, got %d",
+			len(chunk.documents), len(response.Status.DocumentResponses))
+	}
+	if err != nil && (!errors.As(err, &apiErr) || len(response.Status.DocumentResponses) != len(chunk.documents)) {
+		// The whole chunk failed (or the outcome of single rows is unknown)
+		result.failures = chunk.failures(err)
+		return result
+	}
+
+	for i, documentResponse := range response.Status.DocumentResponses {
+		switch documentResponse.Status {
+		case "OK":
+			key, err := response.Status.PrimaryKeySchema.primaryKey(documentResponse.ID)
+			if err != nil {
+				err = fmt.Errorf("failed to decode the inserted primary key: %w", err)
+				result.failures = append(result.failures, InsertManyFailure{Index: chunk.offset + i, Document: chunk.documents[i], Err: err})
+				continue
+			}
+			result.insertedIDs = append(result.insertedIDs, key)
+		case "SKIPPED":
+			// Not attempted after a failure in an ordered insertion
+		default:
+			err := insertManyDocumentError(apiErr, documentResponse.ErrorsIdx, documentResponse.Status)
+			result.failures = append(result.failures, InsertManyFailure{Index: chunk.offset + i, Document: chunk.documents[i], Err: err})
+		}
+	}
+	return result
+}
+
+// FindOne runs a search and returns a row (decoded according to the types of its columns,
+// see Table.Find), or nil if not found. Sort and projection can optionally be passed.
+func (t *Table) FindOne(filter interface{}, options ...*FindOneOptions) (map[string]interface{}, error) {
+	return t.FindOneContext(context.Background(), filter, options...)
+}
+
+// FindOneContext is like FindOne, with a context bounding the operation.
+func (t *Table) FindOneContext(ctx context.Context, filter interface{}, options ...*FindOneOptions) (map[string]interface{}, error) {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	command := findCommand{Filter: normalizeFilter(filter)}
+	if len(options) > 0 && options[0] != nil {
+		opts := options[0]
+		if err := validateSort(opts.Sort); err != nil {
+			return nil, err
+		}
+		command.Sort = opts.Sort
+		command.Projection = opts.Projection
+		if opts.IncludeSimilarity || opts.IncludeSortVector {
+			command.Options = &findCommandOptions{
+				IncludeSimilarity: opts.IncludeSimilarity,
+				IncludeSortVector: opts.IncludeSortVector,
+			}
+		}
+	}
+	requestPayload := struct {
+		FindOne findCommand `json:"findOne"`
+	}{
+		FindOne: command,
+	}
+
+	// The API returns: {"data": {"document": <row>}, "status": {"projectionSchema": {...}}}
+	var response struct {
+		Data struct {
+			Document json.RawMessage `json:"document"`
+		} `json:"data"`
+		Status struct {
+			ProjectionSchema tableSchema `json:"projectionSchema"`
+		} `json:"status"`
+	}
+	if err := t.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+		return nil, err
+	}
+	if isNullDocument(response.Data.Document) {
+		return nil, nil
+	}
+	var row map[string]interface{}
+	if err := decodeRow(response.Data.Document, response.Status.ProjectionSchema, &row); err != nil {
+		return nil, fmt.Errorf("failed to decode row: %w", err)
+	}
+	return row, nil
+}
+
+// Find runs a search and returns a cursor over the matching rows; options may be nil.
+// The rows are decoded according to the types of their columns, as described by the
+// projectionSchema returned by the Data API, so that no precision is lost (e.g. bigint
+// columns are decoded as int64, varint as *big.Int, decimal as json.Number, blob as []byte).
+func (t *Table) Find(filter interface{}, options *FindOptions) *FindCursor {
+	return t.FindContext(context.Background(), filter, options)
+}
+
+// FindContext is like Find. The given context bounds every request issued by the cursor.
+func (t *Table) FindContext(ctx context.Context, filter interface{}, options *FindOptions) *FindCursor {
+	if options == nil {
+		options = &FindOptions{}
+	}
+	if err := validateSort(options.Sort); err != nil {
+		cursor := newFindCursor(ctx, t.commander, nil, options.Limit)
+		cursor.err = err
+		return cursor
+	}
+	command := findCommand{
+		Filter:     normalizeFilter(filter),
+		Sort:       options.Sort,
+		Projection: options.Projection,
+	}
+	commandOptions := findCommandOptions{
+		Limit:             options.Limit,
+		Skip:              options.Skip,
+		IncludeSimilarity: options.IncludeSimilarity,
+		IncludeSortVector: options.IncludeSortVector,
+	}
+
+	fetch := func(ctx context.Context, pageState *string) (*cursorPage, error) {
+		pageOptions := commandOptions
+		pageOptions.PageState = pageState
+		pageCommand := command
+		if pageOptions != (findCommandOptions{}) {
+			pageCommand.Options = &pageOptions
+		}
+		requestPayload := struct {
+			Find findCommand `json:"find"`
+		}{
+			Find: pageCommand,
+		}
+
+		var response struct {
+			Data struct {
+				Documents     []json.RawMessage `json:"documents"`
+				NextPageState *string           `json:"nextPageState"`
+			} `json:"data"`
+			Status struct {
+				ProjectionSchema tableSchema   `json:"projectionSchema"`
+				SortVector       DataAPIVector `json:"sortVector"`
+			} `json:"status"`
+		}
+		if err := t.commander.RequestContext(ctx, requestPayload, &response); err != nil {
+			return nil, err
+		}
+		return &cursorPage{
+			documents:     response.Data.Documents,
+			nextPageState: response.Data.NextPageState,
+			sortVector:    response.Status.SortVector,
+			schema:        response.Status.ProjectionSchema,
+		}, nil
+	}
+
+	cursor := newFindCursor(ctx, t.commander, fetch, options.Limit)
+	cursor.rows = true
+	return cursor
+}
+
+// UpdateOne updates the row matching the filter, which must specify its full primary key.
+// Only the $set and $unset operators are supported (see NewUpdate); as with CQL, a row
+// that does not exist is created, and the Data API reports no counts.
+func (t *Table) UpdateOne(filter interface{}, update interface{}) error {
+	return t.UpdateOneContext(context.Background(), filter, update)
+}
+
+// UpdateOneContext is like UpdateOne, with a context bounding the operation.
+func (t *Table) UpdateOneContext(ctx context.Context, filter interface{}, update interface{}) error {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	if err := validateTableUpdate(update); err != nil {
+		return err
+	}
+	requestPayload := struct {
+		UpdateOne updateCommand `json:"updateOne"`
+	}{
+		UpdateOne: updateCommand{Filter: normalizeFilter(filter), Update: update},
+	}
+
+	var response struct {
+		Status json.RawMessage `json:"status"`
+	}
+	return t.commander.RequestContext(ctx, requestPayload, &response)
+}
+
+// validateTableUpdate checks that an update only uses the operators supported by tables.
+func validateTableUpdate(update interface{}) error {
+	object, _, err := (&encoder{}).encodeObject(update)
+	if err != nil {
+		return err
+	}
+	if len(object) == 0 {
+		return fmt.Errorf("empty update")
+	}
+	for _, field := range object {
+		if field.key != "$set" && field.key != "$unset" {
+			return fmt.Errorf("%w (got %s)", ErrTableUpdateOperator, field.key)
+		}
+	}
+	return nil
+}
+
+// DeleteOne deletes the row matching the filter, which must specify its full primary key.
+// The Data API reports no count.
+func (t *Table) DeleteOne(filter interface{}) error {
+	return t.DeleteOneContext(context.Background(), filter)
+}
+
+// DeleteOneContext is like DeleteOne, with a context bounding the operation.
+func (t *Table) DeleteOneContext(ctx context.Context, filter interface{}) error {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	requestPayload := struct {
+		DeleteOne deleteCommand `json:"deleteOne"`
+	}{
+		DeleteOne: deleteCommand{Filter: normalizeFilter(filter)},
+	}
+
+	var response struct {
+		Status json.RawMessage `json:"status"`
+	}
+	return t.commander.RequestContext(ctx, requestPayload, &response)
+}
+
+// DeleteMany deletes all rows matching the filter, in a single request; the Data API
+// reports no count. A filter encoding to an empty object (nil, an empty map or struct...)
+// is rejected with ErrEmptyFilter (see DeleteAll).
+func (t *Table) DeleteMany(filter interface{}) error {
+	return t.DeleteManyContext(context.Background(), filter)
+}
+
+// DeleteManyContext is like DeleteMany, with a context bounding the operation.
+func (t *Table) DeleteManyContext(ctx context.Context, filter interface{}) error {
+	empty, err := t.commander.encoder().isEmptyFilter(filter)
+	if err != nil {
+		return err
+	}
+	if empty {
+		return ErrEmptyFilter
+	}
+	return t.deleteMany(ctx, filter)
+}
+
+// DeleteAll deletes all rows in the table (truncation), in a single request.
+func (t *Table) DeleteAll() error {
+	return t.DeleteAllContext(context.Background())
+}
+
+// DeleteAllContext is like DeleteAll, with a context bounding the operation.
+func (t *Table) DeleteAllContext(ctx context.Context) error {
+	return t.deleteMany(ctx, map[string]any{})
+}
+
+// deleteMany sends a deleteMany command with the given filter.
+func (t *Table) deleteMany(ctx context.Context, filter interface{}) error {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	requestPayload := struct {
+		DeleteMany deleteCommand `json:"deleteMany"`
+	}{
+		DeleteMany: deleteCommand{Filter: filter},
+	}
+
+	var response struct {
+		Status json.RawMessage `json:"status"`
+	}
+	return t.commander.RequestContext(ctx, requestPayload, &response)
+}
@@ -0,0 +1,232 @@
+package stragollum
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// AlterTableOperation is an operation of Table.Alter: AddColumns, DropColumns, AddVectorize
+// or DropVectorize.
+type AlterTableOperation interface {
+	// alterTableOperation returns the name and the content of the operation in the
+	// alterTable command, or an error if the operation is invalid.
+	alterTableOperation() (string, interface{}, error)
+}
+
+// AddColumns adds columns to a table. Primary key columns cannot be added.
+type AddColumns struct {
+	Columns map[string]TableColumn
+}
+
+// DropColumns drops columns from a table. Primary key columns cannot be dropped.
+type DropColumns struct {
+	Columns []string
+}
+
+// AddVectorize configures an embedding service on existing vector columns.
+type AddVectorize struct {
+	Columns map[string]VectorServiceOptions
+}
+
+// DropVectorize removes the embedding service from vector columns, keeping their data.
+type DropVectorize struct {
+	Columns []string
+}
+
+func (op AddColumns) alterTableOperation() (string, interface{}, error) {
+	if len(op.Columns) == 0 {
+		return "", nil, fmt.Errorf("no columns to add")
+	}
+	for name, column := range op.Columns {
+		if err := column.validate(); err != nil {
+			return "", nil, fmt.Errorf("column %q: %w", name, err)
+		}
+	}
+	return "add", map[string]interface{}{"columns": op.Columns}, nil
+}
+
+func (op DropColumns) alterTableOperation() (string, interface{}, error) {
+	if len(op.Columns) == 0 {
+		return "", nil, fmt.Errorf("no columns to drop")
+	}
+	return "drop", map[string]interface{}{"columns": op.Columns}, nil
+}
+
+func (op AddVectorize) alterTableOperation() (string, interface{}, error) {
+	if len(op.Columns) == 0 {
+		return "", nil, fmt.Errorf("no columns to vectorize")
+	}
+	return "addVectorize", map[string]interface{}{"columns": op.Columns}, nil
+}
+
+func (op DropVectorize) alterTableOperation() (string, interface{}, error) {
+	if len(op.Columns) == 0 {
+		return "", nil, fmt.Errorf("no columns to drop the vectorize service from")
+	}
+	return "dropVectorize", map[string]interface{}{"columns": op.Columns}, nil
+}
+
+// Alter applies an operation to the schema of the table.
+// Returns an error if the operation is invalid, if the API response is not
+// {"status": {"ok": 1}} or if the request fails.
+func (t *Table) Alter(operation AlterTableOperation) error {
+	return t.AlterContext(context.Background(), operation)
+}
+
+// AlterContext is like Alter, with a context bounding the operation.
+func (t *Table) AlterContext(ctx context.Context, operation AlterTableOperation) error {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	if operation == nil {
+		return fmt.Errorf("missing alter operation")
+	}
+	name, content, err := operation.alterTableOperation()
+	if err != nil {
+		return fmt.Errorf("invalid alter operation: %w", err)
+	}
+
+	// The payload is {"alterTable": {"operation": {<name>: <content>}}}
+	requestPayload := struct {
+		AlterTable struct {
+			Operation map[string]interface{} `json:"operation"`
+		} `json:"alterTable"`
+	}{}
+	requestPayload.AlterTable.Operation = map[string]interface{}{name: content}
+
+	return t.commander.requestOk(ctx, requestPayload)
+}
+
+// Definition retrieves the current definition of the table from the Data API.
+// It returns an error if the table does not exist.
+func (t *Table) Definition() (*TableDefinition, error) {
+	return t.DefinitionContext(context.Background())
+}
+
+// DefinitionContext is like Definition, with a context bounding the operation.
+func (t *Table) DefinitionContext(ctx context.Context) (*TableDefinition, error) {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	descriptors, err := t.database.ListTablesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, descriptor := range descriptors {
+		if descriptor.Name == t.name {
+			definition := descriptor.Definition
+			return &definition, nil
+		}
+	}
+	return nil, fmt.Errorf("table %q not found in keyspace %q", t.name, t.keyspace)
+}
+
+// PlanAlter is a dry run of a migration: it compares the desired definition with the
+// current one (see Definition) and returns the operations that Alter should apply, in
+// order, to reach it. No operations are returned if the table is up to date.
+func (t *Table) PlanAlter(desired *TableDefinition) ([]AlterTableOperation, error) {
+	return t.PlanAlterContext(context.Background(), desired)
+}
+
+// PlanAlterContext is like PlanAlter, with a context bounding the operation.
+func (t *Table) PlanAlterContext(ctx context.Context, desired *TableDefinition) ([]AlterTableOperation, error) {
+	current, err := t.DefinitionContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return desired.AlterOperations(current)
+}
+
+// AlterOperations returns the operations turning the current definition of a table into
+// this one: vectorize services are dropped, then columns are dropped, columns are added
+// and vectorize services are added. Changes that cannot be applied by altering the table
+// (to the primary key or to the type of a column) are reported as an error, as is a nil
+// current definition (a table that does not exist must be created, see Database.CreateTable).
+func (td *TableDefinition) AlterOperations(current *TableDefinition) ([]AlterTableOperation, error) {
+	if td == nil {
+		return nil, fmt.Errorf("missing table definition")
+	}
+	if current == nil {
+		return nil, fmt.Errorf("missing current table definition: the table must be created, not altered")
+	}
+	if err := td.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid table definition: %w", err)
+	}
+	if !sameSetting(td.PrimaryKey, current.PrimaryKey) {
+		return nil, fmt.Errorf("primary key cannot be changed (requested %s, existing %s)",
+			describeSetting(td.PrimaryKey), describeSetting(current.PrimaryKey))
+	}
+
+	addColumns := AddColumns{Columns: map[string]TableColumn{}}
+	addVectorize := AddVectorize{Columns: map[string]VectorServiceOptions{}}
+	var dropColumns DropColumns
+	var dropVectorize DropVectorize
+
+	for _, name := range sortedColumnNames(td.Columns) {
+		column := td.Columns[name]
+		existing, ok := current.Columns[name]
+		if !ok {
+			addColumns.Columns[name] = column
+			continue
+		}
+		if !column.sameType(existing) {
+			return nil, fmt.Errorf("type of column %q cannot be changed (requested %s, existing %s)",
+				name, describeSetting(column.typeOnly()), describeSetting(existing.typeOnly()))
+		}
+		if column.Type != ColumnTypeVector || sameSetting(column.Service, existing.Service) {
+			continue
+		}
+		if existing.Service != nil {
+			dropVectorize.Columns = append(dropVectorize.Columns, name)
+		}
+		if column.Service != nil {
+			addVectorize.Columns[name] = *column.Service
+		}
+	}
+	for _, name := range sortedColumnNames(current.Columns) {
+		if _, ok := td.Columns[name]; !ok {
+			dropColumns.Columns = append(dropColumns.Columns, name)
+		}
+	}
+
+	var operations []AlterTableOperation
+	if len(dropVectorize.Columns) > 0 {
+		operations = append(operations, dropVectorize)
+	}
+	if len(dropColumns.Columns) > 0 {
+		operations = append(operations, dropColumns)
+	}
+	if len(addColumns.Columns) > 0 {
+		operations = append(operations, addColumns)
+	}
+	if len(addVectorize.Columns) > 0 {
+		operations = append(operations, addVectorize)
+	}
+	return operations, nil
+}
+
+// typeOnly returns the column without its vectorize service and API support details.
+func (tc TableColumn) typeOnly() TableColumn {
+	return TableColumn{Type: tc.Type, KeyType: tc.KeyType, ValueType: tc.ValueType, Dimension: tc.Dimension}
+}
+
+// sameType tells whether two columns have the same type. A vector column without a
+// dimension (given by its service) matches any dimension.
+func (tc TableColumn) sameType(other TableColumn) bool {
+	requested := tc.typeOnly()
+	if requested.Type == ColumnTypeVector && requested.Dimension == nil {
+		requested.Dimension = other.Dimension
+	}
+	return sameSetting(requested, other.typeOnly())
+}
+
+// sortedColumnNames returns the names of the columns, sorted.
+func sortedColumnNames(columns map[string]TableColumn) []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,408 @@
+package stragollum
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Rows read from tables come with a schema (projectionSchema, primaryKeySchema) giving the
+// type of each column, which drives their decoding into untyped maps:
+//
+//	int, smallint, tinyint        int
+//	bigint, counter               int64
+//	varint                        *big.Int
+//	decimal                       json.Number
+//	float                         float32
+//	double                        float64
+//	timestamp                     time.Time
+//	uuid, timeuuid                UUID
+//	blob                          []byte
+//	vector                        DataAPIVector
+//	list, set                     []interface{}
+//	map                           map[string]interface{} (or map[interface{}]interface{}
+//	                              for non-text keys)
+//
+// Other types (text, date, time, duration, inet, ...) are decoded as strings. Numbers are
+// never decoded as float64 along the way, so that no precision is lost.
+
+// schemaColumn is a column of a schema returned by the Data API.
+type schemaColumn struct {
+	name   string
+	column TableColumn
+}
+
+// tableSchema is a schema returned by the Data API, in the order of its columns.
+type tableSchema []schemaColumn
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the order of the columns.
+func (s *tableSchema) UnmarshalJSON(data []byte) error {
+	names, values, err := decodeOrderedFields(data)
+	if err != nil {
+		return err
+	}
+	schema := make(tableSchema, len(names))
+	for i, name := range names {
+		schema[i].name = name
+		if err := json.Unmarshal(values[i], &schema[i].column); err != nil {
+			return fmt.Errorf("invalid schema for column %q: %w", name, err)
+		}
+	}
+	*s = schema
+	return nil
+}
+
+// column returns the definition of the named column.
+func (s tableSchema) column(name string) (TableColumn, bool) {
+	for _, c := range s {
+		if c.name == name {
+			return c.column, true
+		}
+	}
+	return TableColumn{}, false
+}
+
+// decodeOrderedFields decodes a JSON object into its keys and raw values, in order.
+// A null object has no fields.
+func decodeOrderedFields(raw []byte) ([]string, []json.RawMessage, error) {
+	if isNullDocument(raw) {
+		return nil, nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected a JSON object, got %s", raw)
+	}
+	var keys []string
+	var values []json.RawMessage
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, token.(string))
+		values = append(values, value)
+	}
+	return keys, values, nil
+}
+
+// PrimaryKey is the primary key of a row, as returned by the table insert operations:
+// the values of the primary key columns, in the order of the primary key.
+type PrimaryKey struct {
+	Columns []string
+	Values  []interface{}
+}
+
+// Value returns the value of the given primary key column.
+func (pk PrimaryKey) Value(column string) (interface{}, bool) {
+	for i, c := range pk.Columns {
+		if c == column {
+			return pk.Values[i], true
+		}
+	}
+	return nil, false
+}
+
+// Filter returns a filter matching the row with this primary key.
+func (pk PrimaryKey) Filter() map[string]interface{} {
+	filter := make(map[string]interface{}, len(pk.Columns))
+	for i, c := range pk.Columns {
+		filter[c] = pk.Values[i]
+	}
+	return filter
+}
+
+// primaryKey decodes a primary key returned by the Data API: the array of the values of
+// the primary key columns, described by the schema.
+func (s tableSchema) primaryKey(raw json.RawMessage) (PrimaryKey, error) {
+	var values []interface{}
+	if err := decodeNumbers(raw, &values); err != nil {
+		return PrimaryKey{}, err
+	}
+	if len(values) != len(s) {
+		return PrimaryKey{}, fmt.Errorf("expected %d primary key values, got %s", len(s), raw)
+	}
+	key := PrimaryKey{Columns: make([]string, len(s)), Values: make([]interface{}, len(s))}
+	for i, c := range s {
+		value, err := decodeColumnValue(values[i], c.column)
+		if err != nil {
+			return PrimaryKey{}, fmt.Errorf("column %q: %w", c.name, err)
+		}
+		key.Columns[i], key.Values[i] = c.name, value
+	}
+	return key, nil
+}
+
+// decodeRow decodes a row into v. Untyped targets (*map[string]interface{} and
+// *interface{}) get the values decoded according to the schema; for other targets (e.g.
+// structs) the values are first made decodable by encoding/json (blobs as base64 strings,
+// vectors as arrays), and the numbers landing in interface{} fields are json.Number.
+func decodeRow(raw json.RawMessage, schema tableSchema, v interface{}) error {
+	var fields map[string]interface{}
+	if err := decodeNumbers(raw, &fields); err != nil {
+		return err
+	}
+
+	switch target := v.(type) {
+	case *map[string]interface{}:
+		row, err := decodeRowFields(fields, schema)
+		if err != nil {
+			return err
+		}
+		*target = row
+		return nil
+	case *interface{}:
+		row, err := decodeRowFields(fields, schema)
+		if err != nil {
+			return err
+		}
+		*target = row
+		return nil
+	}
+
+	for name, value := range fields {
+		if column, ok := schema.column(name); ok {
+			fields[name] = normalizeColumnValue(value, column)
+		}
+	}
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return unmarshalDocument(normalized, v, NumberDecodingJSONNumber)
+}
+
+// decodeRowFields decodes the values of a row according to the schema.
+func decodeRowFields(fields map[string]interface{}, schema tableSchema) (map[string]interface{}, error) {
+	for name, value := range fields {
+		column, ok := schema.column(name)
+		if !ok {
+			fields[name] = decodeExtendedValue(value)
+			continue
+		}
+		decoded, err := decodeColumnValue(value, column)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", name, err)
+		}
+		fields[name] = decoded
+	}
+	return fields, nil
+}
+
+// decodeColumnValue converts a value (decoded with json.Number numbers) according to the
+// definition of its column.
+func decodeColumnValue(value interface{}, column TableColumn) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch column.Type {
+	case ColumnTypeInt, ColumnTypeSmallInt, ColumnTypeTinyInt:
+		if n, ok := value.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				return int(i), nil
+			}
+		}
+	case ColumnTypeBigInt, ColumnTypeCounter:
+		if n, ok := value.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				return i, nil
+			}
+		}
+	case ColumnTypeVarInt:
+		if n, ok := value.(json.Number); ok {
+			if i, ok := new(big.Int).SetString(n.String(), 10); ok {
+				return i, nil
+			}
+		}
+	case ColumnTypeDecimal:
+		if n, ok := value.(json.Number); ok {
+			return n, nil
+		}
+	case ColumnTypeFloat, ColumnTypeDouble:
+		if f, ok := decodeFloat(value); ok {
+			if column.Type == ColumnTypeFloat {
+				return float32(f), nil
+			}
+			return f, nil
+		}
+	case ColumnTypeTimestamp:
+		switch v := value.(type) {
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, nil
+			}
+		case map[string]interface{}:
+			if t, ok := decodeWrapper(v); ok {
+				if _, isTime := t.(time.Time); isTime {
+					return t, nil
+				}
+			}
+		}
+	case ColumnTypeUUID, ColumnTypeTimeUUID:
+		switch v := value.(type) {
+		case string:
+			if u, err := ParseUUID(v); err == nil {
+				return u, nil
+			}
+		case map[string]interface{}:
+			if u, ok := decodeWrapper(v); ok {
+				if _, isUUID := u.(UUID); isUUID {
+					return u, nil
+				}
+			}
+		}
+	case ColumnTypeBlob:
+		if object, ok := value.(map[string]interface{}); ok {
+			if encoded, ok := object["$binary"].(string); ok && len(object) == 1 {
+				if b, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+					return b, nil
+				}
+			}
+		}
+	case ColumnTypeVector:
+		if vector, ok := decodeVectorValue(value); ok {
+			return vector, nil
+		}
+	case ColumnTypeList, ColumnTypeSet:
+		if items, ok := value.([]interface{}); ok {
+			element := TableColumn{Type: column.ValueType}
+			for i, item := range items {
+				decoded, err := decodeColumnValue(item, element)
+				if err != nil {
+					return nil, err
+				}
+				items[i] = decoded
+			}
+			return items, nil
+		}
+	case ColumnTypeMap:
+		return decodeMapValue(value, column)
+	default:
+		return decodeExtendedValue(value), nil
+	}
+	return nil, fmt.Errorf("cannot decode %v as %s", value, column.Type)
+}
+
+// decodeFloat reads a float, including the "NaN", "Infinity" and "-Infinity" strings.
+func decodeFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		switch v {
+		case "NaN":
+			return math.NaN(), true
+		case "Infinity":
+			return math.Inf(1), true
+		case "-Infinity":
+			return math.Inf(-1), true
+		}
+	}
+	return 0, false
+}
+
+// decodeVectorValue reads a vector, either binary-encoded or as an array of numbers.
+func decodeVectorValue(value interface{}) (DataAPIVector, bool) {
+	if vector, ok := decodeVectorWrapper(value); ok {
+		return vector, true
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	vector := make(DataAPIVector, len(items))
+	for i, item := range items {
+		f, ok := decodeFloat(item)
+		if !ok {
+			return nil, false
+		}
+		vector[i] = float32(f)
+	}
+	return vector, true
+}
+
+// decodeMapValue reads a map, returned either as an object (text keys) or as an array of
+// [key, value] pairs.
+func decodeMapValue(value interface{}, column TableColumn) (interface{}, error) {
+	keyColumn, valueColumn := TableColumn{Type: column.KeyType}, TableColumn{Type: column.ValueType}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			decoded, err := decodeColumnValue(item, valueColumn)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = decoded
+		}
+		return v, nil
+	case []interface{}:
+		decodedMap := make(map[interface{}]interface{}, len(v))
+		for _, entry := range v {
+			pair, ok := entry.([]interface{})
+			if !ok || len(pair) != 2 {
+				return nil, fmt.Errorf("invalid map entry %v", entry)
+			}
+			key, err := decodeColumnValue(pair[0], keyColumn)
+			if err != nil {
+				return nil, err
+			}
+			if !isHashable(key) {
+				return nil, fmt.Errorf("unsupported map key %v", key)
+			}
+			item, err := decodeColumnValue(pair[1], valueColumn)
+			if err != nil {
+				return nil, err
+			}
+			decodedMap[key] = item
+		}
+		return decodedMap, nil
+	}
+	return nil, fmt.Errorf("cannot decode %v as map", value)
+}
+
+// isHashable tells whether a decoded value can be a Go map key.
+func isHashable(value interface{}) bool {
+	switch value.(type) {
+	case []byte, []interface{}, map[string]interface{}, map[interface{}]interface{}, DataAPIVector:
+		return false
+	}
+	return true
+}
+
+// normalizeColumnValue rewrites a value so that encoding/json can decode it into the Go
+// type matching its column: blobs as base64 strings, binary-encoded vectors as arrays.
+func normalizeColumnValue(value interface{}, column TableColumn) interface{} {
+	switch column.Type {
+	case ColumnTypeBlob:
+		if object, ok := value.(map[string]interface{}); ok && len(object) == 1 {
+			if encoded, ok := object["$binary"].(string); ok {
+				return encoded
+			}
+		}
+	case ColumnTypeVector:
+		if vector, ok := decodeVectorWrapper(value); ok {
+			return []float32(vector)
+		}
+	case ColumnTypeList, ColumnTypeSet:
+		if items, ok := value.([]interface{}); ok {
+			for i, item := range items {
+				items[i] = normalizeColumnValue(item, TableColumn{Type: column.ValueType})
+			}
+		}
+	case ColumnTypeMap:
+		if object, ok := value.(map[string]interface{}); ok {
+			for key, item := range object {
+				object[key] = normalizeColumnValue(item, TableColumn{Type: column.ValueType})
+			}
+		}
+	}
+	return value
+}
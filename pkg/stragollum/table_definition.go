@@ -0,0 +1,261 @@
+package stragollum
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Column types of tables.
+const (
+	ColumnTypeText      = "text"
+	ColumnTypeAscii     = "ascii"
+	ColumnTypeInt       = "int"
+	ColumnTypeSmallInt  = "smallint"
+	ColumnTypeTinyInt   = "tinyint"
+	ColumnTypeBigInt    = "bigint"
+	ColumnTypeCounter   = "counter"
+	ColumnTypeVarInt    = "varint"
+	ColumnTypeFloat     = "float"
+	ColumnTypeDouble    = "double"
+	ColumnTypeDecimal   = "decimal"
+	ColumnTypeBoolean   = "boolean"
+	ColumnTypeTimestamp = "timestamp"
+	ColumnTypeDate      = "date"
+	ColumnTypeTime      = "time"
+	ColumnTypeDuration  = "duration"
+	ColumnTypeUUID      = "uuid"
+	ColumnTypeTimeUUID  = "timeuuid"
+	ColumnTypeInet      = "inet"
+	ColumnTypeBlob      = "blob"
+	ColumnTypeList      = "list"
+	ColumnTypeSet       = "set"
+	ColumnTypeMap       = "map"
+	ColumnTypeVector    = "vector"
+)
+
+// TableDefinition represents the configuration for creating a table: its columns and
+// its primary key.
+type TableDefinition struct {
+	Columns    map[string]TableColumn `json:"columns"`
+	PrimaryKey TablePrimaryKey        `json:"primaryKey"`
+}
+
+// TableColumn is the definition of a column. KeyType and ValueType are the element types
+// of list, set and map columns; Dimension and Service apply to vector columns.
+type TableColumn struct {
+	Type      string                `json:"type"`
+	KeyType   string                `json:"keyType,omitempty"`
+	ValueType string                `json:"valueType,omitempty"`
+	Dimension *int                  `json:"dimension,omitempty"`
+	Service   *VectorServiceOptions `json:"service,omitempty"`
+	// APISupport describes the level of support of the column by the Data API, as found
+	// in the definitions read from the Data API (e.g. for column types it cannot handle).
+	APISupport map[string]any `json:"apiSupport,omitempty"`
+}
+
+// TablePrimaryKey is the primary key of a table: the partition columns, and the
+// clustering columns with their order.
+type TablePrimaryKey struct {
+	PartitionBy   []string           `json:"partitionBy"`
+	PartitionSort []ClusteringColumn `json:"partitionSort,omitempty"`
+}
+
+// ClusteringColumn is a clustering column of a primary key, with its order (SortAscending
+// or SortDescending).
+type ClusteringColumn struct {
+	Column string
+	Order  int
+}
+
+// NewTableDefinition creates an empty TableDefinition.
+func NewTableDefinition() *TableDefinition {
+	return &TableDefinition{Columns: map[string]TableColumn{}}
+}
+
+// addColumn sets the definition of a column.
+func (td *TableDefinition) addColumn(name string, column TableColumn) *TableDefinition {
+	if td.Columns == nil {
+		td.Columns = map[string]TableColumn{}
+	}
+	td.Columns[name] = column
+	return td
+}
+
+// AddColumn adds a column of a scalar type (see the ColumnType constants).
+func (td *TableDefinition) AddColumn(name string, columnType string) *TableDefinition {
+	return td.addColumn(name, TableColumn{Type: columnType})
+}
+
+// AddListColumn adds a list column, with elements of the given type.
+func (td *TableDefinition) AddListColumn(name string, valueType string) *TableDefinition {
+	return td.addColumn(name, TableColumn{Type: ColumnTypeList, ValueType: valueType})
+}
+
+// AddSetColumn adds a set column, with elements of the given type.
+func (td *TableDefinition) AddSetColumn(name string, valueType string) *TableDefinition {
+	return td.addColumn(name, TableColumn{Type: ColumnTypeSet, ValueType: valueType})
+}
+
+// AddMapColumn adds a map column, with keys and values of the given types.
+func (td *TableDefinition) AddMapColumn(name string, keyType string, valueType string) *TableDefinition {
+	return td.addColumn(name, TableColumn{Type: ColumnTypeMap, KeyType: keyType, ValueType: valueType})
+}
+
+// AddVectorColumn adds a vector column. The dimension can be 0 if a service is given
+// whose model determines it; service may be nil.
+func (td *TableDefinition) AddVectorColumn(name string, dimension int, service *VectorServiceOptions) *TableDefinition {
+	column := TableColumn{Type: ColumnTypeVector, Service: service}
+	if dimension != 0 {
+		column.Dimension = &dimension
+	}
+	return td.addColumn(name, column)
+}
+
+// WithPartitionBy sets the partition columns of the primary key.
+func (td *TableDefinition) WithPartitionBy(columns ...string) *TableDefinition {
+	td.PrimaryKey.PartitionBy = columns
+	return td
+}
+
+// WithPartitionSort appends a clustering column to the primary key, with the given order
+// (SortAscending or SortDescending).
+func (td *TableDefinition) WithPartitionSort(column string, order int) *TableDefinition {
+	td.PrimaryKey.PartitionSort = append(td.PrimaryKey.PartitionSort, ClusteringColumn{Column: column, Order: order})
+	return td
+}
+
+// Validate ensures the TableDefinition has valid configuration.
+func (td *TableDefinition) Validate() error {
+	if len(td.Columns) == 0 {
+		return fmt.Errorf("table definition has no columns")
+	}
+	for name, column := range td.Columns {
+		if err := column.validate(); err != nil {
+			return fmt.Errorf("column %q: %w", name, err)
+		}
+	}
+	if len(td.PrimaryKey.PartitionBy) == 0 {
+		return fmt.Errorf("primary key has no partition columns")
+	}
+	keyColumns := map[string]bool{}
+	for _, name := range td.PrimaryKey.PartitionBy {
+		if err := td.checkKeyColumn(name, keyColumns); err != nil {
+			return err
+		}
+	}
+	for _, clustering := range td.PrimaryKey.PartitionSort {
+		if err := td.checkKeyColumn(clustering.Column, keyColumns); err != nil {
+			return err
+		}
+		if clustering.Order != SortAscending && clustering.Order != SortDescending {
+			return fmt.Errorf("invalid order %d for clustering column %q", clustering.Order, clustering.Column)
+		}
+	}
+	return nil
+}
+
+// checkKeyColumn checks that a primary key column is defined and not repeated.
+func (td *TableDefinition) checkKeyColumn(name string, seen map[string]bool) error {
+	if _, ok := td.Columns[name]; !ok {
+		return fmt.Errorf("primary key column %q is not defined", name)
+	}
+	if seen[name] {
+		return fmt.Errorf("primary key column %q given more than once", name)
+	}
+	seen[name] = true
+	return nil
+}
+
+// validate checks the settings of a column against its type.
+func (tc TableColumn) validate() error {
+	switch tc.Type {
+	case "":
+		return fmt.Errorf("missing type")
+	case ColumnTypeList, ColumnTypeSet:
+		if tc.ValueType == "" {
+			return fmt.Errorf("%s column needs a value type", tc.Type)
+		}
+	case ColumnTypeMap:
+		if tc.KeyType == "" || tc.ValueType == "" {
+			return fmt.Errorf("map column needs a key type and a value type")
+		}
+	case ColumnTypeVector:
+		if tc.Dimension != nil && *tc.Dimension <= 0 {
+			return fmt.Errorf("vector dimension must be positive")
+		}
+		if tc.Dimension == nil && tc.Service == nil {
+			return fmt.Errorf("vector column needs a dimension or a service")
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, also accepting the short form of a column
+// (its type only, e.g. "text").
+func (tc *TableColumn) UnmarshalJSON(data []byte) error {
+	var columnType string
+	if err := json.Unmarshal(data, &columnType); err == nil {
+		*tc = TableColumn{Type: columnType}
+		return nil
+	}
+	type plain TableColumn
+	var column plain
+	if err := json.Unmarshal(data, &column); err != nil {
+		return err
+	}
+	*tc = TableColumn(column)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, also accepting the short form of a primary
+// key (the name of its only partition column).
+func (pk *TablePrimaryKey) UnmarshalJSON(data []byte) error {
+	var column string
+	if err := json.Unmarshal(data, &column); err == nil {
+		*pk = TablePrimaryKey{PartitionBy: []string{column}}
+		return nil
+	}
+	var raw struct {
+		PartitionBy   []string        `json:"partitionBy"`
+		PartitionSort json.RawMessage `json:"partitionSort"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	sort, err := decodeClusteringColumns(raw.PartitionSort)
+	if err != nil {
+		return err
+	}
+	*pk = TablePrimaryKey{PartitionBy: raw.PartitionBy, PartitionSort: sort}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler: the clustering columns are an object, in order.
+func (pk TablePrimaryKey) MarshalJSON() ([]byte, error) {
+	object := orderedObject{{key: "partitionBy", value: pk.PartitionBy}}
+	if len(pk.PartitionSort) > 0 {
+		sort := make(orderedObject, len(pk.PartitionSort))
+		for i, clustering := range pk.PartitionSort {
+			sort[i] = orderedField{key: clustering.Column, value: clustering.Order}
+		}
+		object = append(object, orderedField{key: "partitionSort", value: sort})
+	}
+	return json.Marshal(object)
+}
+
+// decodeClusteringColumns decodes a partitionSort object, keeping the order of its fields.
+func decodeClusteringColumns(raw json.RawMessage) ([]ClusteringColumn, error) {
+	names, values, err := decodeOrderedFields(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid partitionSort: %w", err)
+	}
+	var columns []ClusteringColumn
+	for i, name := range names {
+		var order int
+		if err := json.Unmarshal(values[i], &order); err != nil {
+			return nil, fmt.Errorf("invalid partitionSort: %w", err)
+		}
+		columns = append(columns, ClusteringColumn{Column: name, Order: order})
+	}
+	return columns, nil
+}
@@ -0,0 +1,280 @@
+package stragollum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Targets of an index on a map column (see CreateIndexOptions.MapTarget).
+const (
+	IndexMapEntries = ""
+	IndexMapKeys    = "$keys"
+	IndexMapValues  = "$values"
+)
+
+// Types of table indexes, as reported by Table.ListIndexes.
+const (
+	IndexTypeRegular = "regular"
+	IndexTypeVector  = "vector"
+	IndexTypeText    = "text"
+)
+
+// CreateIndexOptions holds the optional parameters of Table.CreateIndex.
+type CreateIndexOptions struct {
+	// MapTarget, for map columns, makes the index cover the keys (IndexMapKeys) or the
+	// values (IndexMapValues) instead of the entries.
+	MapTarget string
+	// Ascii, CaseSensitive and Normalize control how text and ascii columns are indexed
+	// (Data API defaults when nil).
+	Ascii         *bool
+	CaseSensitive *bool
+	Normalize     *bool
+	// IfNotExists makes the creation succeed (without changes) if the index already exists.
+	IfNotExists bool
+}
+
+// CreateVectorIndexOptions holds the optional parameters of Table.CreateVectorIndex.
+type CreateVectorIndexOptions struct {
+	// IfNotExists makes the creation succeed (without changes) if the index already exists.
+	IfNotExists bool
+}
+
+// CreateTextIndexOptions holds the optional parameters of Table.CreateTextIndex.
+type CreateTextIndexOptions struct {
+	// IfNotExists makes the creation succeed (without changes) if the index already exists.
+	IfNotExists bool
+}
+
+// TableIndexDescriptor describes an index of a table: its name, its type (see the
+// IndexType constants) and its definition.
+type TableIndexDescriptor struct {
+	Name       string               `json:"name"`
+	IndexType  string               `json:"indexType"`
+	Definition TableIndexDefinition `json:"definition"`
+}
+
+// TableIndexDefinition is the definition of an index: the indexed column, the map target
+// for indexes on the keys or values of a map column, and the options of the index.
+type TableIndexDefinition struct {
+	Column    string
+	MapTarget string
+	Options   map[string]any
+	// APISupport describes the level of support of the index by the Data API, if limited.
+	APISupport map[string]any
+}
+
+// UnmarshalJSON implements json.Unmarshaler, reading the column either as a name or as
+// a {<column>: <map target>} object.
+func (d *TableIndexDefinition) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Column     json.RawMessage `json:"column"`
+		Options    map[string]any  `json:"options"`
+		APISupport map[string]any  `json:"apiSupport"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*d = TableIndexDefinition{Options: raw.Options, APISupport: raw.APISupport}
+	if err := json.Unmarshal(raw.Column, &d.Column); err == nil {
+		return nil
+	}
+	var target map[string]string
+	if err := json.Unmarshal(raw.Column, &target); err != nil || len(target) != 1 {
+		return fmt.Errorf("invalid index column: %s", raw.Column)
+	}
+	for column, mapTarget := range target {
+		d.Column, d.MapTarget = column, mapTarget
+	}
+	return nil
+}
+
+// indexColumn returns the column of an index definition in the Data API format.
+func indexColumn(column string, mapTarget string) interface{} {
+	if mapTarget == IndexMapEntries {
+		return column
+	}
+	return map[string]string{column: mapTarget}
+}
+
+// indexCommand is the content of the createIndex, createVectorIndex and createTextIndex
+// commands.
+type indexCommand struct {
+	Name       string `json:"name"`
+	Definition struct {
+		Column  interface{}    `json:"column"`
+		Options map[string]any `json:"options,omitempty"`
+	} `json:"definition"`
+	Options *indexCreationOptions `json:"options,omitempty"`
+}
+
+// indexCreationOptions are the options of the index creation commands.
+type indexCreationOptions struct {
+	IfNotExists bool `json:"ifNotExists"`
+}
+
+// newIndexCommand builds the content of an index creation command.
+func newIndexCommand(name string, column interface{}, options map[string]any, ifNotExists bool) indexCommand {
+	command := indexCommand{Name: name}
+	command.Definition.Column = column
+	if len(options) > 0 {
+		command.Definition.Options = options
+	}
+	if ifNotExists {
+		command.Options = &indexCreationOptions{IfNotExists: true}
+	}
+	return command
+}
+
+// CreateIndex creates an index on a column, enabling filtering on it.
+// Returns an error if the API response is not {"status": {"ok": 1}} or if the request fails.
+func (t *Table) CreateIndex(name string, column string, options ...*CreateIndexOptions) error {
+	return t.CreateIndexContext(context.Background(), name, column, options...)
+}
+
+// CreateIndexContext is like CreateIndex, with a context bounding the operation.
+func (t *Table) CreateIndexContext(ctx context.Context, name string, column string, options ...*CreateIndexOptions) error {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	opts := &CreateIndexOptions{}
+	if len(options) > 0 && options[0] != nil {
+		opts = options[0]
+	}
+	if opts.MapTarget != IndexMapEntries && opts.MapTarget != IndexMapKeys && opts.MapTarget != IndexMapValues {
+		return fmt.Errorf("invalid map target %q", opts.MapTarget)
+	}
+	indexOptions := map[string]any{}
+	for key, value := range map[string]*bool{"ascii": opts.Ascii, "caseSensitive": opts.CaseSensitive, "normalize": opts.Normalize} {
+		if value != nil {
+			indexOptions[key] = *value
+		}
+	}
+
+	// The payload is {"createIndex": {"name": <name>, "definition": {"column": <column>, "options": {...}}}}
+	requestPayload := struct {
+		CreateIndex indexCommand `json:"createIndex"`
+	}{
+		CreateIndex: newIndexCommand(name, indexColumn(column, opts.MapTarget), indexOptions, opts.IfNotExists),
+	}
+	return t.commander.requestOk(ctx, requestPayload)
+}
+
+// CreateVectorIndex creates an index on a vector column, enabling vector (ANN) search on
+// it. The metric (e.g. "cosine") and the source model of the embeddings can be empty,
+// leaving the Data API defaults.
+// Returns an error if the API response is not {"status": {"ok": 1}} or if the request fails.
+func (t *Table) CreateVectorIndex(name string, column string, metric string, sourceModel string, options ...*CreateVectorIndexOptions) error {
+	return t.CreateVectorIndexContext(context.Background(), name, column, metric, sourceModel, options...)
+}
+
+// CreateVectorIndexContext is like CreateVectorIndex, with a context bounding the operation.
+func (t *Table) CreateVectorIndexContext(ctx context.Context, name string, column string, metric string, sourceModel string, options ...*CreateVectorIndexOptions) error {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	indexOptions := map[string]any{}
+	if metric != "" {
+		indexOptions["metric"] = metric
+	}
+	if sourceModel != "" {
+		indexOptions["sourceModel"] = sourceModel
+	}
+	ifNotExists := len(options) > 0 && options[0] != nil && options[0].IfNotExists
+
+	requestPayload := struct {
+		CreateVectorIndex indexCommand `json:"createVectorIndex"`
+	}{
+		CreateVectorIndex: newIndexCommand(name, column, indexOptions, ifNotExists),
+	}
+	return t.commander.requestOk(ctx, requestPayload)
+}
+
+// CreateTextIndex creates a text index on a column, enabling lexical search on it. The
+// analyzer is either the name of a built-in analyzer (e.g. "standard") or an analyzer
+// configuration; nil leaves the Data API default.
+// Returns an error if the API response is not {"status": {"ok": 1}} or if the request fails.
+func (t *Table) CreateTextIndex(name string, column string, analyzer any, options ...*CreateTextIndexOptions) error {
+	return t.CreateTextIndexContext(context.Background(), name, column, analyzer, options...)
+}
+
+// CreateTextIndexContext is like CreateTextIndex, with a context bounding the operation.
+func (t *Table) CreateTextIndexContext(ctx context.Context, name string, column string, analyzer any, options ...*CreateTextIndexOptions) error {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	indexOptions := map[string]any{}
+	if analyzer != nil {
+		indexOptions["analyzer"] = analyzer
+	}
+	ifNotExists := len(options) > 0 && options[0] != nil && options[0].IfNotExists
+
+	requestPayload := struct {
+		CreateTextIndex indexCommand `json:"createTextIndex"`
+	}{
+		CreateTextIndex: newIndexCommand(name, column, indexOptions, ifNotExists),
+	}
+	return t.commander.requestOk(ctx, requestPayload)
+}
+
+// ListIndexNames retrieves the names of the indexes of the table.
+func (t *Table) ListIndexNames() ([]string, error) {
+	return t.ListIndexNamesContext(context.Background())
+}
+
+// ListIndexNamesContext is like ListIndexNames, with a context bounding the operation.
+func (t *Table) ListIndexNamesContext(ctx context.Context) ([]string, error) {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	requestPayload := struct {
+		ListIndexes struct{} `json:"listIndexes"`
+	}{}
+
+	// The API returns: {"status": {"indexes": [<name>, ...]}}
+	var response struct {
+		Status struct {
+			Indexes []string `json:"indexes"`
+		} `json:"status"`
+	}
+
+	err := t.commander.RequestContext(ctx, requestPayload, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Status.Indexes, nil
+}
+
+// ListIndexes retrieves the indexes of the table, with their definitions.
+func (t *Table) ListIndexes() ([]TableIndexDescriptor, error) {
+	return t.ListIndexesContext(context.Background())
+}
+
+// ListIndexesContext is like ListIndexes, with a context bounding the operation.
+func (t *Table) ListIndexesContext(ctx context.Context) ([]TableIndexDescriptor, error) {
+	ctx, cancel := t.commander.operationContext(ctx)
+	defer cancel()
+
+	// The payload is {"listIndexes": {"options": {"explain": true}}}
+	requestPayload := struct {
+		ListIndexes struct {
+			Options struct {
+				Explain bool `json:"explain"`
+			} `json:"options"`
+		} `json:"listIndexes"`
+	}{}
+	requestPayload.ListIndexes.Options.Explain = true
+
+	// The API returns: {"status": {"indexes": [{"name": <name>, "indexType": <type>, "definition": <definition>}]}}
+	var response struct {
+		Status struct {
+			Indexes []TableIndexDescriptor `json:"indexes"`
+		} `json:"status"`
+	}
+
+	err := t.commander.RequestContext(ctx, requestPayload, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Status.Indexes, nil
+}
@@ -0,0 +1,84 @@
+package stragollum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeoutOptions holds the default timeouts applied to Data API calls.
+// A zero (or negative) duration means no timeout.
+type TimeoutOptions struct {
+	// RequestTimeout bounds each single HTTP request to the Data API.
+	RequestTimeout time.Duration
+	// OperationTimeout bounds a whole operation, which may span several HTTP requests
+	// (e.g. a paginated read or a chunked insertion).
+	OperationTimeout time.Duration
+}
+
+// TimeoutKind tells which of the configured timeouts has expired.
+type TimeoutKind string
+
+// Constants for TimeoutKind type
+const (
+	TimeoutKindRequest   TimeoutKind = "request"
+	TimeoutKindOperation TimeoutKind = "operation"
+)
+
+// DataAPITimeoutError is returned when one of the configured timeouts (see TimeoutOptions) expires.
+// It unwraps to context.DeadlineExceeded, so errors.Is(err, context.DeadlineExceeded) also holds.
+// Deadlines set by the caller on its own context are not reported with this type.
+type DataAPITimeoutError struct {
+	Kind    TimeoutKind
+	Timeout time.Duration
+}
+
+// Error implements the error interface.
+func (e *DataAPITimeoutError) Error() string {
+	return fmt.Sprintf("data API %s timeout of %s exceeded", e.Kind, e.Timeout)
+}
+
+// Unwrap returns context.DeadlineExceeded.
+func (e *DataAPITimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// operationDeadlineKey is the context key under which an operationDeadline is stored.
+type operationDeadlineKey struct{}
+
+// operationDeadline keeps track of a context bounded by an operation timeout,
+// so that its expiration can be told apart from that of the caller's context.
+type operationDeadline struct {
+	ctx     context.Context
+	parent  context.Context
+	timeout time.Duration
+}
+
+// withOperationTimeout derives a context bounded by the given operation timeout (if positive).
+func withOperationTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	opCtx, cancel := context.WithTimeout(ctx, timeout)
+	deadline := &operationDeadline{ctx: opCtx, parent: ctx, timeout: timeout}
+	return context.WithValue(opCtx, operationDeadlineKey{}, deadline), cancel
+}
+
+// timeoutError returns a *DataAPITimeoutError if err was caused by the expiration of one of
+// the configured timeouts: reqCtx is the per-request context, derived from ctx.
+// It returns nil otherwise.
+func timeoutError(ctx context.Context, reqCtx context.Context, requestTimeout time.Duration, err error) error {
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+		return nil
+	}
+	if ctx.Err() == nil && errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+		return &DataAPITimeoutError{Kind: TimeoutKindRequest, Timeout: requestTimeout}
+	}
+	if deadline, ok := ctx.Value(operationDeadlineKey{}).(*operationDeadline); ok {
+		if errors.Is(deadline.ctx.Err(), context.DeadlineExceeded) && deadline.parent.Err() == nil {
+			return &DataAPITimeoutError{Kind: TimeoutKindOperation, Timeout: deadline.timeout}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,386 @@
+package stragollum
+
+import "context"
+
+// TypedCollection is a view over a Collection whose documents are values of type T,
+// typically a struct: documents are encoded/decoded following the json struct tags
+// of T, with the reserved fields (_id, $vector, $vectorize) also reachable through
+// the DataAPITag. Reads return *T values (nil if no document is found).
+//
+// Unlike what was first planned, Collection itself is not generic (with
+// Collection[map[string]any] as the untyped case): that would change the signature
+// of every existing Collection method and of Database.GetCollection. And since Go
+// methods cannot have type parameters, Database.GetCollection cannot return a typed
+// collection either. Typed collections are therefore a separate type, obtained with
+// the GetTypedCollection and CreateTypedCollection functions (or NewTypedCollection
+// to wrap an existing Collection). The untyped Collection API, working with
+// map[string]interface{} documents, is unchanged and always available through
+// Collection().
+type TypedCollection[T any] struct {
+	collection *Collection
+}
+
+// NewTypedCollection wraps an (untyped) Collection into a TypedCollection.
+func NewTypedCollection[T any](collection *Collection) *TypedCollection[T] {
+	return &TypedCollection[T]{collection: collection}
+}
+
+// GetTypedCollection is the typed counterpart of Database.GetCollection.
+func GetTypedCollection[T any](db *Database, name string, token *string) *TypedCollection[T] {
+	return NewTypedCollection[T](db.GetCollection(name, token))
+}
+
+// CreateTypedCollection is the typed counterpart of Database.CreateCollection.
+func CreateTypedCollection[T any](db *Database, name string, definition *CollectionDefinition) (*TypedCollection[T], error) {
+	return CreateTypedCollectionContext[T](context.Background(), db, name, definition)
+}
+
+// CreateTypedCollectionContext is like CreateTypedCollection, with a context bounding the operation.
+func CreateTypedCollectionContext[T any](ctx context.Context, db *Database, name string, definition *CollectionDefinition) (*TypedCollection[T], error) {
+	collection, err := db.CreateCollectionContext(ctx, name, definition)
+	if err != nil {
+		return nil, err
+	}
+	return NewTypedCollection[T](collection), nil
+}
+
+// Collection returns the underlying untyped Collection.
+func (tc *TypedCollection[T]) Collection() *Collection {
+	return tc.collection
+}
+
+// Name returns the name of the collection.
+func (tc *TypedCollection[T]) Name() string {
+	return tc.collection.Name()
+}
+
+// findAndModify runs a find-and-modify command, returning the document as a *T (nil if none).
+func (tc *TypedCollection[T]) findAndModify(ctx context.Context, commandName string, command findAndModifyCommand, status interface{}) (*T, error) {
+	var document T
+	found, err := tc.collection.findAndModify(ctx, commandName, command, &document, status)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// InsertOne inserts a single document into the collection, returning its ID.
+func (tc *TypedCollection[T]) InsertOne(document T) (DocumentID, error) {
+	return tc.collection.InsertOne(document)
+}
+
+// InsertOneContext is like InsertOne, with a context bounding the operation.
+func (tc *TypedCollection[T]) InsertOneContext(ctx context.Context, document T) (DocumentID, error) {
+	return tc.collection.InsertOneContext(ctx, document)
+}
+
+// InsertMany inserts the given documents into the collection (see Collection.InsertMany).
+func (tc *TypedCollection[T]) InsertMany(documents []T, options *InsertManyOptions) ([]DocumentID, error) {
+	return tc.InsertManyContext(context.Background(), documents, options)
+}
+
+// InsertManyContext is like InsertMany, with a context bounding the whole operation.
+func (tc *TypedCollection[T]) InsertManyContext(ctx context.Context, documents []T, options *InsertManyOptions) ([]DocumentID, error) {
+	untyped := make([]interface{}, len(documents))
+	for i, document := range documents {
+		untyped[i] = document
+	}
+	return tc.collection.InsertManyContext(ctx, untyped, options)
+}
+
+// FindOne runs a search and returns a document, or nil if not found.
+func (tc *TypedCollection[T]) FindOne(filter interface{}, options ...*FindOneOptions) (*T, error) {
+	return tc.FindOneContext(context.Background(), filter, options...)
+}
+
+// FindOneContext is like FindOne, with a context bounding the operation.
+func (tc *TypedCollection[T]) FindOneContext(ctx context.Context, filter interface{}, options ...*FindOneOptions) (*T, error) {
+	var document T
+	found, err := tc.collection.findOne(ctx, filter, &document, options...)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// Find runs a search and returns a cursor over the matching documents (see Collection.Find).
+func (tc *TypedCollection[T]) Find(filter interface{}, options *FindOptions) *TypedCursor[T] {
+	return tc.FindContext(context.Background(), filter, options)
+}
+
+// FindContext is like Find. The given context bounds every request issued by the cursor.
+func (tc *TypedCollection[T]) FindContext(ctx context.Context, filter interface{}, options *FindOptions) *TypedCursor[T] {
+	return &TypedCursor[T]{cursor: tc.collection.FindContext(ctx, filter, options)}
+}
+
+// UpdateOne updates a single document matching the filter (see Collection.UpdateOne).
+func (tc *TypedCollection[T]) UpdateOne(filter interface{}, update interface{}, options *UpdateOneOptions) (*UpdateResult, error) {
+	return tc.collection.UpdateOne(filter, update, options)
+}
+
+// UpdateOneContext is like UpdateOne, with a context bounding the operation.
+func (tc *TypedCollection[T]) UpdateOneContext(ctx context.Context, filter interface{}, update interface{}, options *UpdateOneOptions) (*UpdateResult, error) {
+	return tc.collection.UpdateOneContext(ctx, filter, update, options)
+}
+
+// UpdateMany updates all documents matching the filter (see Collection.UpdateMany).
+func (tc *TypedCollection[T]) UpdateMany(filter interface{}, update interface{}, options *UpdateManyOptions) (*UpdateResult, error) {
+	return tc.collection.UpdateMany(filter, update, options)
+}
+
+// UpdateManyContext is like UpdateMany, with a context bounding the whole operation.
+func (tc *TypedCollection[T]) UpdateManyContext(ctx context.Context, filter interface{}, update interface{}, options *UpdateManyOptions) (*UpdateResult, error) {
+	return tc.collection.UpdateManyContext(ctx, filter, update, options)
+}
+
+// ReplaceOne replaces a single document matching the filter (see Collection.ReplaceOne).
+func (tc *TypedCollection[T]) ReplaceOne(filter interface{}, replacement T, options *ReplaceOneOptions) (*UpdateResult, error) {
+	return tc.collection.ReplaceOne(filter, replacement, options)
+}
+
+// ReplaceOneContext is like ReplaceOne, with a context bounding the operation.
+func (tc *TypedCollection[T]) ReplaceOneContext(ctx context.Context, filter interface{}, replacement T, options *ReplaceOneOptions) (*UpdateResult, error) {
+	return tc.collection.ReplaceOneContext(ctx, filter, replacement, options)
+}
+
+// FindOneAndUpdate atomically updates a document matching the filter and returns it
+// (see Collection.FindOneAndUpdate).
+func (tc *TypedCollection[T]) FindOneAndUpdate(filter interface{}, update interface{}, options *FindOneAndUpdateOptions) (*T, *UpdateResult, error) {
+	return tc.FindOneAndUpdateContext(context.Background(), filter, update, options)
+}
+
+// FindOneAndUpdateContext is like FindOneAndUpdate, with a context bounding the operation.
+func (tc *TypedCollection[T]) FindOneAndUpdateContext(ctx context.Context, filter interface{}, update interface{}, options *FindOneAndUpdateOptions) (*T, *UpdateResult, error) {
+	var status updateStatus
+	document, err := tc.findAndModify(ctx, "findOneAndUpdate", findOneAndUpdateCommand(filter, update, options), &status)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &UpdateResult{}
+	status.addTo(result)
+	return document, result, nil
+}
+
+// FindOneAndReplace atomically replaces a document matching the filter and returns it
+// (see Collection.FindOneAndReplace).
+func (tc *TypedCollection[T]) FindOneAndReplace(filter interface{}, replacement T, options *FindOneAndReplaceOptions) (*T, *UpdateResult, error) {
+	return tc.FindOneAndReplaceContext(context.Background(), filter, replacement, options)
+}
+
+// FindOneAndReplaceContext is like FindOneAndReplace, with a context bounding the operation.
+func (tc *TypedCollection[T]) FindOneAndReplaceContext(ctx context.Context, filter interface{}, replacement T, options *FindOneAndReplaceOptions) (*T, *UpdateResult, error) {
+	var status updateStatus
+	document, err := tc.findAndModify(ctx, "findOneAndReplace", findOneAndReplaceCommand(filter, replacement, options), &status)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &UpdateResult{}
+	status.addTo(result)
+	return document, result, nil
+}
+
+// FindOneAndDelete atomically deletes a document matching the filter and returns it
+// (see Collection.FindOneAndDelete).
+func (tc *TypedCollection[T]) FindOneAndDelete(filter interface{}, options *FindOneAndDeleteOptions) (*T, *DeleteResult, error) {
+	return tc.FindOneAndDeleteContext(context.Background(), filter, options)
+}
+
+// FindOneAndDeleteContext is like FindOneAndDelete, with a context bounding the operation.
+func (tc *TypedCollection[T]) FindOneAndDeleteContext(ctx context.Context, filter interface{}, options *FindOneAndDeleteOptions) (*T, *DeleteResult, error) {
+	var status deleteStatus
+	document, err := tc.findAndModify(ctx, "findOneAndDelete", findOneAndDeleteCommand(filter, options), &status)
+	if err != nil {
+		return nil, nil, err
+	}
+	return document, &DeleteResult{DeletedCount: status.DeletedCount}, nil
+}
+
+// DeleteOne deletes a single document matching the filter (see Collection.DeleteOne).
+func (tc *TypedCollection[T]) DeleteOne(filter interface{}, options *DeleteOneOptions) (*DeleteResult, error) {
+	return tc.collection.DeleteOne(filter, options)
+}
+
+// DeleteOneContext is like DeleteOne, with a context bounding the operation.
+func (tc *TypedCollection[T]) DeleteOneContext(ctx context.Context, filter interface{}, options *DeleteOneOptions) (*DeleteResult, error) {
+	return tc.collection.DeleteOneContext(ctx, filter, options)
+}
+
+// DeleteMany deletes all documents matching a non-empty filter (see Collection.DeleteMany).
+func (tc *TypedCollection[T]) DeleteMany(filter interface{}) (*DeleteResult, error) {
+	return tc.collection.DeleteMany(filter)
+}
+
+// DeleteManyContext is like DeleteMany, with a context bounding the whole operation.
+func (tc *TypedCollection[T]) DeleteManyContext(ctx context.Context, filter interface{}) (*DeleteResult, error) {
+	return tc.collection.DeleteManyContext(ctx, filter)
+}
+
+// DeleteAll deletes all documents in the collection (see Collection.DeleteAll).
+func (tc *TypedCollection[T]) DeleteAll() error {
+	return tc.collection.DeleteAll()
+}
+
+// DeleteAllContext is like DeleteAll, with a context bounding the operation.
+func (tc *TypedCollection[T]) DeleteAllContext(ctx context.Context) error {
+	return tc.collection.DeleteAllContext(ctx)
+}
+
+// CountDocuments returns the exact number of documents matching the filter, up to
+// upperBound (see Collection.CountDocuments).
+func (tc *TypedCollection[T]) CountDocuments(filter interface{}, upperBound int) (int, error) {
+	return tc.collection.CountDocuments(filter, upperBound)
+}
+
+// CountDocumentsContext is like CountDocuments, with a context bounding the operation.
+func (tc *TypedCollection[T]) CountDocumentsContext(ctx context.Context, filter interface{}, upperBound int) (int, error) {
+	return tc.collection.CountDocumentsContext(ctx, filter, upperBound)
+}
+
+// EstimatedDocumentCount returns an approximate count of all documents in the collection.
+func (tc *TypedCollection[T]) EstimatedDocumentCount() (int, error) {
+	return tc.collection.EstimatedDocumentCount()
+}
+
+// EstimatedDocumentCountContext is like EstimatedDocumentCount, with a context bounding the operation.
+func (tc *TypedCollection[T]) EstimatedDocumentCountContext(ctx context.Context) (int, error) {
+	return tc.collection.EstimatedDocumentCountContext(ctx)
+}
+
+// Distinct returns the distinct values of the given key among the documents matching
+// the filter (see Collection.Distinct).
+func (tc *TypedCollection[T]) Distinct(key string, filter interface{}) ([]interface{}, error) {
+	return tc.collection.Distinct(key, filter)
+}
+
+// DistinctContext is like Distinct, with a context bounding the whole operation.
+func (tc *TypedCollection[T]) DistinctContext(ctx context.Context, key string, filter interface{}) ([]interface{}, error) {
+	return tc.collection.DistinctContext(ctx, key, filter)
+}
+
+// FindByVector runs a vector search, returning the (up to) k most similar documents
+// (see Collection.FindByVector).
+func (tc *TypedCollection[T]) FindByVector(vector []float32, k int, filter interface{}, options *VectorSearchOptions) (*VectorSearchResult[T], error) {
+	return tc.FindByVectorContext(context.Background(), vector, k, filter, options)
+}
+
+// FindByVectorContext is like FindByVector, with a context bounding the operation.
+func (tc *TypedCollection[T]) FindByVectorContext(ctx context.Context, vector []float32, k int, filter interface{}, options *VectorSearchOptions) (*VectorSearchResult[T], error) {
+	return vectorSearch[T](ctx, tc.collection, VectorSort(vector), k, filter, options)
+}
+
+// FindByVectorize runs a vector search by the vector computed from the given text
+// (see Collection.FindByVectorize).
+func (tc *TypedCollection[T]) FindByVectorize(text string, k int, filter interface{}, options *VectorSearchOptions) (*VectorSearchResult[T], error) {
+	return tc.FindByVectorizeContext(context.Background(), text, k, filter, options)
+}
+
+// FindByVectorizeContext is like FindByVectorize, with a context bounding the operation.
+func (tc *TypedCollection[T]) FindByVectorizeContext(ctx context.Context, text string, k int, filter interface{}, options *VectorSearchOptions) (*VectorSearchResult[T], error) {
+	return vectorSearch[T](ctx, tc.collection, VectorizeSort(text), k, filter, options)
+}
+
+// FindByLexical runs a lexical search, returning a cursor over the matching documents
+// in order of relevance (see Collection.FindByLexical).
+func (tc *TypedCollection[T]) FindByLexical(query string, filter interface{}, options *LexicalSearchOptions) *TypedCursor[T] {
+	return tc.FindByLexicalContext(context.Background(), query, filter, options)
+}
+
+// FindByLexicalContext is like FindByLexical. The given context bounds every request issued by the cursor.
+func (tc *TypedCollection[T]) FindByLexicalContext(ctx context.Context, query string, filter interface{}, options *LexicalSearchOptions) *TypedCursor[T] {
+	return &TypedCursor[T]{cursor: tc.collection.FindByLexicalContext(ctx, query, filter, options)}
+}
+
+// FindAndRerank runs a hybrid search, returning a cursor over the reranked results
+// (see Collection.FindAndRerank).
+func (tc *TypedCollection[T]) FindAndRerank(filter interface{}, options *FindAndRerankOptions) *RerankCursor[T] {
+	return tc.FindAndRerankContext(context.Background(), filter, options)
+}
+
+// FindAndRerankContext is like FindAndRerank. The given context bounds the request issued by the cursor.
+func (tc *TypedCollection[T]) FindAndRerankContext(ctx context.Context, filter interface{}, options *FindAndRerankOptions) *RerankCursor[T] {
+	return findAndRerank[T](ctx, tc.collection, filter, options)
+}
+
+// TypedCursor iterates over the results of a find operation on a TypedCollection,
+// decoding each document into a T. See FindCursor.
+type TypedCursor[T any] struct {
+	cursor *FindCursor
+}
+
+// Next advances the cursor to the next document (see FindCursor.Next).
+func (c *TypedCursor[T]) Next() bool {
+	return c.cursor.Next()
+}
+
+// Current decodes and returns the current document.
+func (c *TypedCursor[T]) Current() (*T, error) {
+	var document T
+	if err := c.cursor.Decode(&document); err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// Decode decodes the current document into v.
+func (c *TypedCursor[T]) Decode(v interface{}) error {
+	return c.cursor.Decode(v)
+}
+
+// Similarity returns the similarity score of the current document (see FindCursor.Similarity).
+func (c *TypedCursor[T]) Similarity() (float64, error) {
+	return c.cursor.Similarity()
+}
+
+// All decodes and returns all the remaining documents, then closes the cursor.
+func (c *TypedCursor[T]) All() ([]T, error) {
+	documents := []T{}
+	err := c.cursor.All(&documents)
+	return documents, err
+}
+
+// Iter returns a function iterating over the remaining documents, in the shape of an iter.Seq2
+// (see FindCursor.Iter).
+func (c *TypedCursor[T]) Iter() func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		for c.Next() {
+			document, err := c.Current()
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yield(*document, nil) {
+				return
+			}
+		}
+		if err := c.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (c *TypedCursor[T]) Err() error {
+	return c.cursor.Err()
+}
+
+// Close closes the cursor. It always returns nil.
+func (c *TypedCursor[T]) Close() error {
+	return c.cursor.Close()
+}
+
+// Buffered returns the number of documents fetched from the API but not yet consumed.
+func (c *TypedCursor[T]) Buffered() int {
+	return c.cursor.Buffered()
+}
+
+// Consumed returns the number of documents the cursor has advanced through so far.
+func (c *TypedCursor[T]) Consumed() int {
+	return c.cursor.Consumed()
+}
+
+// SortVector returns the vector used for sorting (see FindCursor.SortVector).
+func (c *TypedCursor[T]) SortVector() ([]float32, error) {
+	return c.cursor.SortVector()
+}
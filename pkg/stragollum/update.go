@@ -0,0 +1,102 @@
+package stragollum
+
+// Update is an update document for the update family of commands, e.g.
+// {"$set": {"a": 1}, "$inc": {"n": 2}}. Build it with NewUpdate and the operator
+// methods below, which can be chained; being a map, it can also be inspected directly.
+type Update map[string]any
+
+// NewUpdate creates an empty Update.
+func NewUpdate() Update {
+	return Update{}
+}
+
+// withOperator sets field to value under the given update operator.
+func (u Update) withOperator(operator string, field string, value any) Update {
+	fields, ok := u[operator].(map[string]any)
+	if !ok {
+		fields = map[string]any{}
+		u[operator] = fields
+	}
+	fields[field] = value
+	return u
+}
+
+// Set sets field to value ($set).
+func (u Update) Set(field string, value any) Update {
+	return u.withOperator("$set", field, value)
+}
+
+// Unset removes field ($unset).
+func (u Update) Unset(field string) Update {
+	return u.withOperator("$unset", field, "")
+}
+
+// Inc increments field by amount ($inc).
+func (u Update) Inc(field string, amount any) Update {
+	return u.withOperator("$inc", field, amount)
+}
+
+// Mul multiplies field by factor ($mul).
+func (u Update) Mul(field string, factor any) Update {
+	return u.withOperator("$mul", field, factor)
+}
+
+// Min sets field to value if value is less than the current value ($min).
+func (u Update) Min(field string, value any) Update {
+	return u.withOperator("$min", field, value)
+}
+
+// Max sets field to value if value is greater than the current value ($max).
+func (u Update) Max(field string, value any) Update {
+	return u.withOperator("$max", field, value)
+}
+
+// Rename renames field to newName ($rename).
+func (u Update) Rename(field string, newName string) Update {
+	return u.withOperator("$rename", field, newName)
+}
+
+// CurrentDate sets field to the current date ($currentDate).
+func (u Update) CurrentDate(field string) Update {
+	return u.withOperator("$currentDate", field, true)
+}
+
+// SetOnInsert sets field to value only if the update results in an upsert ($setOnInsert).
+func (u Update) SetOnInsert(field string, value any) Update {
+	return u.withOperator("$setOnInsert", field, value)
+}
+
+// Push appends value to the array field ($push).
+func (u Update) Push(field string, value any) Update {
+	return u.withOperator("$push", field, value)
+}
+
+// PushEach appends all values to the array field ($push with $each).
+// If a position is given, the values are inserted at that position ($position).
+func (u Update) PushEach(field string, values []any, position ...int) Update {
+	spec := map[string]any{"$each": values}
+	if len(position) > 0 {
+		spec["$position"] = position[0]
+	}
+	return u.withOperator("$push", field, spec)
+}
+
+// AddToSet adds value to the array field, unless already present ($addToSet).
+func (u Update) AddToSet(field string, value any) Update {
+	return u.withOperator("$addToSet", field, value)
+}
+
+// AddToSetEach adds each of the values to the array field, unless already present ($addToSet with $each).
+func (u Update) AddToSetEach(field string, values []any) Update {
+	return u.withOperator("$addToSet", field, map[string]any{"$each": values})
+}
+
+// PopFirst removes the first element of the array field ($pop with -1).
+func (u Update) PopFirst(field string) Update {
+	return u.withOperator("$pop", field, -1)
+}
+
+// PopLast removes the last element of the array field ($pop with 1).
+func (u Update) PopLast(field string) Update {
+	return u.withOperator("$pop", field, 1)
+}
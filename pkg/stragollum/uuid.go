@@ -0,0 +1,161 @@
+package stragollum
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// UUID is a UUID, stored by the Data API as {"$uuid": "<canonical form>"}: values of this
+// type are serialized as such, and such wrappers are decoded back into UUID values.
+// Any version can be held; versions 1, 4, 6 and 7 can be generated.
+type UUID [16]byte
+
+// gregorianOffset is the number of 100ns intervals between the start of the Gregorian
+// calendar (1582-10-15), the epoch of version 1 and 6 UUIDs, and the Unix epoch.
+const gregorianOffset = 122192928000000000
+
+// ParseUUID parses a UUID in the canonical form (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx).
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	digits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return UUID{}, fmt.Errorf("invalid UUID %q: %w", s, err)
+	}
+	return u, nil
+}
+
+// randomUUID returns a UUID with random bits, and the given version and the RFC 4122 variant set.
+func randomUUID(version byte) (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return u, fmt.Errorf("failed to generate UUID: %w", err)
+	}
+	u[6] = (u[6] & 0x0f) | version<<4
+	u[8] = (u[8] & 0x3f) | 0x80
+	return u, nil
+}
+
+// NewUUIDv4 generates a random (version 4) UUID.
+func NewUUIDv4() (UUID, error) {
+	return randomUUID(4)
+}
+
+// NewUUIDv7 generates a time-ordered (version 7) UUID, based on the Unix time in milliseconds.
+func NewUUIDv7() (UUID, error) {
+	u, err := randomUUID(7)
+	if err != nil {
+		return u, err
+	}
+	ms := uint64(time.Now().UnixMilli())
+	u[0], u[1], u[2], u[3], u[4], u[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+	return u, nil
+}
+
+// gregorianTimestamp returns the current time as 100ns intervals since the Gregorian epoch.
+func gregorianTimestamp() uint64 {
+	return uint64(time.Now().UnixNano()/100) + gregorianOffset
+}
+
+// NewUUIDv1 generates a time-based (version 1) UUID, with a random clock sequence and node.
+func NewUUIDv1() (UUID, error) {
+	u, err := randomUUID(1)
+	if err != nil {
+		return u, err
+	}
+	ts := gregorianTimestamp()
+	binary.BigEndian.PutUint32(u[0:4], uint32(ts))
+	binary.BigEndian.PutUint16(u[4:6], uint16(ts>>32))
+	binary.BigEndian.PutUint16(u[6:8], uint16(ts>>48)&0x0fff|0x1000)
+	u[10] |= 0x01 // random node: multicast bit set
+	return u, nil
+}
+
+// NewUUIDv6 generates a time-ordered (version 6) UUID, with a random clock sequence and node.
+func NewUUIDv6() (UUID, error) {
+	u, err := randomUUID(6)
+	if err != nil {
+		return u, err
+	}
+	ts := gregorianTimestamp()
+	binary.BigEndian.PutUint32(u[0:4], uint32(ts>>28))
+	binary.BigEndian.PutUint16(u[4:6], uint16(ts>>12))
+	binary.BigEndian.PutUint16(u[6:8], uint16(ts)&0x0fff|0x6000)
+	u[10] |= 0x01 // random node: multicast bit set
+	return u, nil
+}
+
+// String returns the canonical form of the UUID.
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:36], u[10:16])
+	return string(buf[:])
+}
+
+// Version returns the version of the UUID.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// encodeTree implements treeEncoder.
+func (u UUID) encodeTree(e *encoder) (any, error) {
+	if e.tables {
+		return u.String(), nil
+	}
+	return map[string]any{"$uuid": u.String()}, nil
+}
+
+// MarshalJSON implements json.Marshaler, producing the {"$uuid": ...} wrapper.
+func (u UUID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"$uuid": u.String()})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting both the {"$uuid": ...} wrapper and a plain string.
+func (u *UUID) UnmarshalJSON(data []byte) error {
+	s, err := unwrapExtendedString(data, "$uuid")
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseUUID(s)
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// unwrapExtendedString decodes either a {"<wrapper>": "<string>"} object or a plain JSON string.
+func unwrapExtendedString(data []byte, wrapper string) (string, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var wrapped map[string]string
+		if err := json.Unmarshal(trimmed, &wrapped); err != nil {
+			return "", err
+		}
+		s, ok := wrapped[wrapper]
+		if !ok || len(wrapped) != 1 {
+			return "", fmt.Errorf("expected a %s object, got %s", wrapper, trimmed)
+		}
+		return s, nil
+	}
+	var s string
+	if err := json.Unmarshal(trimmed, &s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
@@ -0,0 +1,110 @@
+package stragollum
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// VectorEncoding is the serialization of DataAPIVector values in the requests.
+type VectorEncoding string
+
+// Constants for VectorEncoding type
+const (
+	// VectorEncodingArray sends vectors as plain arrays of numbers (the default).
+	VectorEncodingArray VectorEncoding = "array"
+	// VectorEncodingBinary sends vectors as {"$binary": "<base64>"} objects, holding the
+	// big-endian float32 components: more compact, and faster to produce, than arrays.
+	VectorEncodingBinary VectorEncoding = "binary"
+)
+
+// DataAPIVector is a vector (embedding), serialized in the requests according to the
+// VectorEncoding in effect (see DataAPIClient.WithVectorEncoding). It decodes from
+// either the binary or the array representation.
+//
+// Use it as the type of the vector field of document structs, e.g.:
+//
+//	type Item struct {
+//		Embedding stragollum.DataAPIVector `json:"embedding" dataapi:"vector"`
+//	}
+type DataAPIVector []float32
+
+var dataAPIVectorType = reflect.TypeOf(DataAPIVector(nil))
+
+// binaryVector is the binary representation of a vector.
+type binaryVector struct {
+	Binary string `json:"$binary"`
+}
+
+// encodeBinaryVector packs the vector components as big-endian float32 values, base64-encoded.
+func encodeBinaryVector(vector []float32) binaryVector {
+	packed := make([]byte, 4*len(vector))
+	for i, component := range vector {
+		binary.BigEndian.PutUint32(packed[4*i:], math.Float32bits(component))
+	}
+	return binaryVector{Binary: base64.StdEncoding.EncodeToString(packed)}
+}
+
+// decodeBinaryVector is the inverse of encodeBinaryVector.
+func decodeBinaryVector(encoded string) ([]float32, error) {
+	packed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid binary vector: %w", err)
+	}
+	if len(packed)%4 != 0 {
+		return nil, fmt.Errorf("invalid binary vector: %d bytes is not a multiple of 4", len(packed))
+	}
+	vector := make([]float32, len(packed)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.BigEndian.Uint32(packed[4*i:]))
+	}
+	return vector, nil
+}
+
+// MarshalJSON implements json.Marshaler, using the array representation. (In the requests,
+// the representation is chosen by the VectorEncoding in effect.)
+func (v DataAPIVector) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]float32(v))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting both the binary and the array representation.
+func (v *DataAPIVector) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if string(trimmed) == "null" {
+		*v = nil
+		return nil
+	}
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var encoded binaryVector
+		if err := json.Unmarshal(trimmed, &encoded); err != nil {
+			return err
+		}
+		vector, err := decodeBinaryVector(encoded.Binary)
+		if err != nil {
+			return err
+		}
+		*v = vector
+		return nil
+	}
+	var vector []float32
+	if err := json.Unmarshal(trimmed, &vector); err != nil {
+		return err
+	}
+	*v = vector
+	return nil
+}
+
+// encodeVector converts a DataAPIVector into its JSON-ready representation for a request.
+func (e *encoder) encodeVector(v DataAPIVector) any {
+	if v == nil {
+		return nil
+	}
+	if e.vectorEncoding == VectorEncodingBinary {
+		return encodeBinaryVector(v)
+	}
+	return []float32(v)
+}
@@ -0,0 +1,112 @@
+package stragollum_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+const collectionNotExistResponse = `{"errors":[{"errorCode":"COLLECTION_NOT_EXIST","message":"Collection does not exist: nope","family":"REQUEST","scope":"DATABASE","title":"Collection does not exist","id":"e-123"}]}`
+
+func newErrorServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestDataAPICommander_RequestWithAPIErrors(t *testing.T) {
+	server := newErrorServer(t, `{"status":{"insertedIds":["a"]},"errors":[{"errorCode":"E1","message":"first"},{"errorCode":"E2","message":"second"}]}`)
+	defer server.Close()
+
+	commander := stragollum.NewDataAPICommander(server.URL, nil)
+	requestObj := map[string]any{"insertMany": map[string]any{}}
+	var response struct {
+		Status struct {
+			InsertedIds []string `json:"insertedIds"`
+		} `json:"status"`
+	}
+	err := commander.Request(requestObj, &response)
+	if err == nil {
+		t.Fatal("Expected an error for a response with an errors array, got nil")
+	}
+
+	var apiErr *stragollum.DataAPIResponseError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected a *DataAPIResponseError, got %T: %v", err, err)
+	}
+	if len(apiErr.Errors) != 2 {
+		t.Fatalf("Expected 2 error descriptors, got %d", len(apiErr.Errors))
+	}
+	if codes := apiErr.ErrorCodes(); codes[0] != "E1" || codes[1] != "E2" {
+		t.Errorf("Unexpected error codes: %v", codes)
+	}
+	if !apiErr.HasErrorCode("E2") || apiErr.HasErrorCode("E3") {
+		t.Errorf("HasErrorCode gave unexpected results")
+	}
+	if apiErr.RawResponse["status"] == nil {
+		t.Errorf("Expected raw response to carry the status, got %v", apiErr.RawResponse)
+	}
+	if apiErr.Command == nil {
+		t.Errorf("Expected the command to be attached to the error")
+	}
+	// Partial results are still exposed
+	if len(response.Status.InsertedIds) != 1 || response.Status.InsertedIds[0] != "a" {
+		t.Errorf("Expected partial status to be unmarshalled, got %v", response.Status.InsertedIds)
+	}
+}
+
+func TestAPIErrors_DatabaseAndCollection(t *testing.T) {
+	server := newErrorServer(t, collectionNotExistResponse)
+	defer server.Close()
+
+	token := "dummy"
+	env := stragollum.EnvironmentProd
+	client := stragollum.NewDataAPIClient(&env, &token)
+	db := client.GetDatabase(server.URL, nil, "ks1")
+	collection := db.GetCollection("nope", nil)
+
+	checkError := func(t *testing.T, err error) {
+		t.Helper()
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		var apiErr *stragollum.DataAPIResponseError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected a *DataAPIResponseError, got %T: %v", err, err)
+		}
+		d := apiErr.Errors[0]
+		if d.ErrorCode != "COLLECTION_NOT_EXIST" || d.Message != "Collection does not exist: nope" ||
+			d.Family != "REQUEST" || d.Scope != "DATABASE" || d.Title != "Collection does not exist" || d.ID != "e-123" {
+			t.Errorf("Unexpected error descriptor: %+v", d)
+		}
+	}
+
+	t.Run("ListCollectionNames", func(t *testing.T) {
+		_, err := db.ListCollectionNames()
+		checkError(t, err)
+	})
+	t.Run("CreateCollection", func(t *testing.T) {
+		_, err := db.CreateCollection("nope", stragollum.NewCollectionDefinition())
+		checkError(t, err)
+	})
+	t.Run("DropCollection", func(t *testing.T) {
+		checkError(t, db.DropCollection("nope"))
+	})
+	t.Run("InsertOne", func(t *testing.T) {
+		_, err := collection.InsertOne(map[string]any{"a": 1})
+		checkError(t, err)
+	})
+	t.Run("FindOne", func(t *testing.T) {
+		doc, err := collection.FindOne(map[string]any{})
+		checkError(t, err)
+		if doc != nil {
+			t.Errorf("Expected nil document, got %v", doc)
+		}
+	})
+}
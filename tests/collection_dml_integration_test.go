@@ -72,7 +72,7 @@ func TestInsertOne_Integration(t *testing.T) {
 	}
 
 	// Validate the document ID
-	if docID == "" {
+	if docID.IsZero() {
 		t.Error("Expected non-empty document ID, got empty string")
 	} else {
 		t.Logf("Successfully inserted document with ID: %s", docID)
@@ -91,7 +91,7 @@ func TestInsertOne_Integration(t *testing.T) {
 			t.Logf("Successfully read back document: %s", string(jsonResult))
 			if id, ok := result["_id"].(string); ok {
 				// id contains the document ID as a string
-				if id != docID {
+				if id != docID.String() {
 					t.Errorf("Expected document ID %s, got %s", docID, id)
 				} else {
 					t.Logf("Successfully validated document ID: %s", id)
@@ -103,6 +103,15 @@ func TestInsertOne_Integration(t *testing.T) {
 			t.Errorf("FindOne returned nil document")
 		}
 	}
+	// Read all documents through a cursor
+	var documents []map[string]interface{}
+	if err := collection.Find(map[string]interface{}{}, nil).All(&documents); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(documents) == 0 {
+		t.Errorf("Find returned no documents")
+	}
+
 	// Cleanup: We don't delete the collection as it might be used for future tests
 	// and deleting/creating collections too often might hit rate limits
 }
@@ -0,0 +1,68 @@
+package stragollum_test
+
+import (
+	"errors"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+func TestCollection_CountDocuments(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if name != "countDocuments" {
+			t.Errorf("Unexpected command %q", name)
+		}
+		if command["filter"].(map[string]any)["color"] != "red" {
+			t.Errorf("Unexpected filter: %v", command["filter"])
+		}
+	}, `{"status": {"count": 42}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	count, err := collection.CountDocuments(map[string]any{"color": "red"}, 100)
+	if err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	if count != 42 {
+		t.Errorf("Expected 42, got %d", count)
+	}
+
+	_, err = collection.CountDocuments(map[string]any{"color": "red"}, 10)
+	var tooMany *stragollum.TooManyDocumentsToCountError
+	if !errors.As(err, &tooMany) || tooMany.Limit != 10 || tooMany.HitServerLimit {
+		t.Errorf("Expected a TooManyDocumentsToCountError for the upper bound, got %v", err)
+	}
+}
+
+func TestCollection_CountDocumentsServerLimit(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if filter, ok := command["filter"].(map[string]any); !ok || len(filter) != 0 {
+			t.Errorf("Expected an empty filter, got %v", command["filter"])
+		}
+	}, `{"status": {"count": 1000, "moreData": true}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	_, err := collection.CountDocuments(nil, 5000)
+	var tooMany *stragollum.TooManyDocumentsToCountError
+	if !errors.As(err, &tooMany) || tooMany.Limit != 1000 || !tooMany.HitServerLimit {
+		t.Errorf("Expected a TooManyDocumentsToCountError for the server limit, got %v", err)
+	}
+}
+
+func TestCollection_EstimatedDocumentCount(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if name != "estimatedDocumentCount" || len(command) != 0 {
+			t.Errorf("Unexpected command %q: %v", name, command)
+		}
+	}, `{"status": {"count": 123456}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	count, err := collection.EstimatedDocumentCount()
+	if err != nil {
+		t.Fatalf("EstimatedDocumentCount failed: %v", err)
+	}
+	if count != 123456 {
+		t.Errorf("Expected 123456, got %d", count)
+	}
+}
@@ -0,0 +1,126 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"strings"
+	"testing"
+)
+
+// newCreateCollectionServer answers findCollections with the given collections (raw JSON)
+// and createCollection with success, recording the names of the received commands.
+func newCreateCollectionServer(t *testing.T, rawCollections string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var commands []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		for command := range payload {
+			commands = append(commands, command)
+		}
+		if _, ok := payload["findCollections"]; ok {
+			fmt.Fprintf(w, `{"status": {"collections": %s}}`, rawCollections)
+			return
+		}
+		fmt.Fprint(w, `{"status": {"ok": 1}}`)
+	}))
+	return server, &commands
+}
+
+const existingVectorCollection = `[{"name": "vectors", "options": {
+	"vector": {"dimension": 1024, "metric": "cosine", "sourceModel": "other"},
+	"indexing": {"deny": ["blob"]},
+	"lexical": {"enabled": true, "analyzer": "standard"},
+	"rerank": {"enabled": true, "service": {"provider": "nvidia", "modelName": "nvidia/llama-3.2-nv-rerankqa-1b-v2"}}
+}}]`
+
+func TestDatabase_CreateCollectionCheckExists(t *testing.T) {
+	checkExists := &stragollum.CreateCollectionOptions{CheckExists: true}
+
+	t.Run("matching", func(t *testing.T) {
+		server, commands := newCreateCollectionServer(t, existingVectorCollection)
+		defer server.Close()
+		db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+
+		// Settings left to the defaults are not compared
+		definition := stragollum.NewCollectionDefinition().
+			WithVectorDimension(1024).
+			WithIndexing(map[string]any{"deny": []string{"blob"}})
+		collection, err := db.CreateCollection("vectors", definition, checkExists)
+		if err != nil {
+			t.Fatalf("CreateCollection failed: %v", err)
+		}
+		if collection.Name() != "vectors" {
+			t.Errorf("Unexpected collection: %s", collection.Name())
+		}
+		if strings.Join(*commands, ",") != "findCollections" {
+			t.Errorf("Expected only the existence check, got %v", *commands)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		server, commands := newCreateCollectionServer(t, existingVectorCollection)
+		defer server.Close()
+		db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+
+		if _, err := db.CreateCollection("other", nil, checkExists); err != nil {
+			t.Fatalf("CreateCollection failed: %v", err)
+		}
+		if strings.Join(*commands, ",") != "findCollections,createCollection" {
+			t.Errorf("Expected the check and the creation, got %v", *commands)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		server, commands := newCreateCollectionServer(t, existingVectorCollection)
+		defer server.Close()
+		db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+
+		definition := stragollum.NewCollectionDefinition().
+			WithVectorDimension(768).
+			WithVectorMetric("dot_product").
+			WithLexical("standard", false).
+			WithRerank(&stragollum.RerankServiceOptions{Provider: "nvidia", ModelName: "other-model"})
+		_, err := db.CreateCollection("vectors", definition, checkExists)
+		var mismatch *stragollum.CollectionDefinitionMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Fatalf("Expected a *CollectionDefinitionMismatchError, got %T: %v", err, err)
+		}
+		var fields []string
+		for _, d := range mismatch.Differences {
+			fields = append(fields, d.Field)
+		}
+		expected := "vector.dimension,vector.metric,indexing,lexical.enabled,rerank.service.modelName"
+		if strings.Join(fields, ",") != expected {
+			t.Errorf("Unexpected differences: %v", fields)
+		}
+		if mismatch.Differences[0].Requested != 768 || mismatch.Differences[0].Existing != 1024 {
+			t.Errorf("Unexpected dimension difference: %+v", mismatch.Differences[0])
+		}
+		if !strings.Contains(err.Error(), `indexing (requested none, existing {"deny":["blob"]})`) {
+			t.Errorf("Unexpected message: %v", err)
+		}
+		if len(*commands) != 1 {
+			t.Errorf("Expected no creation attempt, got %v", *commands)
+		}
+	})
+
+	t.Run("vector presence", func(t *testing.T) {
+		server, _ := newCreateCollectionServer(t, `[{"name": "plain", "options": {}}]`)
+		defer server.Close()
+		db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+
+		_, err := db.CreateCollection("plain", stragollum.NewCollectionDefinition().WithVectorDimension(3), checkExists)
+		var mismatch *stragollum.CollectionDefinitionMismatchError
+		if !errors.As(err, &mismatch) || len(mismatch.Differences) != 1 || mismatch.Differences[0].Field != "vector" {
+			t.Fatalf("Expected a vector mismatch, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+func TestCollection_DeleteOne(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		deleteOne, ok := payload["deleteOne"].(map[string]any)
+		if !ok {
+			t.Fatalf("Payload missing deleteOne: %v", payload)
+		}
+		if deleteOne["filter"].(map[string]any)["kind"] != "k" {
+			t.Errorf("Unexpected filter: %v", deleteOne["filter"])
+		}
+		if vector, ok := deleteOne["sort"].(map[string]any)["$vector"].([]any); !ok || len(vector) != 2 {
+			t.Errorf("Unexpected sort: %v", deleteOne["sort"])
+		}
+		fmt.Fprint(w, `{"status": {"deletedCount": 1}}`)
+	}))
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	result, err := collection.DeleteOne(
+		map[string]any{"kind": "k"},
+		&stragollum.DeleteOneOptions{Sort: map[string]any{"$vector": []float32{0.1, 0.2}}},
+	)
+	if err != nil {
+		t.Fatalf("DeleteOne failed: %v", err)
+	}
+	if result.DeletedCount != 1 {
+		t.Errorf("Expected 1 deleted document, got %d", result.DeletedCount)
+	}
+}
+
+func TestCollection_DeleteMany(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if payload["deleteMany"].(map[string]any)["filter"].(map[string]any)["old"] != true {
+			t.Errorf("Unexpected payload: %v", payload)
+		}
+		calls++
+		if calls < 3 {
+			fmt.Fprint(w, `{"status": {"deletedCount": 20, "moreData": true}}`)
+			return
+		}
+		fmt.Fprint(w, `{"status": {"deletedCount": 7}}`)
+	}))
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	result, err := collection.DeleteMany(map[string]any{"old": true})
+	if err != nil {
+		t.Fatalf("DeleteMany failed: %v", err)
+	}
+	if result.DeletedCount != 47 || calls != 3 {
+		t.Errorf("Expected 47 deleted documents over 3 calls, got %d over %d", result.DeletedCount, calls)
+	}
+
+	type optionalFilter struct {
+		Kind string `json:"kind,omitempty"`
+	}
+	emptyFilters := []interface{}{
+		nil, map[string]any{}, map[string]interface{}{}, struct{}{}, &struct{}{}, optionalFilter{},
+		json.RawMessage("{}"), json.RawMessage(" { } "), json.RawMessage("null"),
+	}
+	for _, emptyFilter := range emptyFilters {
+		calls = 0
+		if _, err := collection.DeleteMany(emptyFilter); err != stragollum.ErrEmptyFilter {
+			t.Errorf("Expected ErrEmptyFilter for %v, got %v", emptyFilter, err)
+		}
+		if calls != 0 {
+			t.Errorf("No request should be issued for an empty filter")
+		}
+	}
+
+	// Struct and raw filters with conditions are sent
+	structFilter := struct {
+		Old bool `json:"old"`
+	}{Old: true}
+	for _, filter := range []interface{}{structFilter, json.RawMessage(`{"old": true}`)} {
+		calls = 2
+		if result, err := collection.DeleteMany(filter); err != nil || result.DeletedCount != 7 {
+			t.Errorf("Unexpected result for %v: %+v, %v", filter, result, err)
+		}
+	}
+}
+
+func TestCollection_DeleteAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		filter, ok := payload["deleteMany"].(map[string]any)["filter"].(map[string]any)
+		if !ok || len(filter) != 0 {
+			t.Errorf("Expected an empty filter, got %v", payload)
+		}
+		fmt.Fprint(w, `{"status": {"deletedCount": -1}}`)
+	}))
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	if err := collection.DeleteAll(); err != nil {
+		t.Fatalf("DeleteAll failed: %v", err)
+	}
+}
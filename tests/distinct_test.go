@@ -0,0 +1,110 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"stragollum/pkg/stragollum"
+	"testing"
+	"time"
+)
+
+// newDistinctServer serves the given documents as the (single-page) result of a find,
+// checking that the projection is the expected one.
+func newDistinctServer(t *testing.T, expectedProjection string, documents string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		projection, _ := payload["find"]["projection"].(map[string]any)
+		if len(projection) != 1 || projection[expectedProjection] != true {
+			t.Errorf("Unexpected projection: %v", projection)
+		}
+		fmt.Fprintf(w, `{"data": {"documents": %s, "nextPageState": null}}`, documents)
+	}))
+}
+
+func TestCollection_Distinct(t *testing.T) {
+	testCases := []struct {
+		name       string
+		key        string
+		projection string
+		documents  string
+		expected   []interface{}
+	}{
+		{
+			name:       "dotted path",
+			key:        "metadata.tenant",
+			projection: "metadata.tenant",
+			documents:  `[{"metadata": {"tenant": "a"}}, {"metadata": {"tenant": "b"}}, {"metadata": {"tenant": "a"}}, {"metadata": {}}, {}]`,
+			expected:   []interface{}{"a", "b"},
+		},
+		{
+			name:       "arrays are flattened and traversed",
+			key:        "items.sku",
+			projection: "items.sku",
+			documents:  `[{"items": [{"sku": "x"}, {"sku": ["y", "z"]}]}, {"items": {"sku": "x"}}]`,
+			expected:   []interface{}{"x", "y", "z"},
+		},
+		{
+			name:       "nested arrays are unwound one level only",
+			key:        "tags",
+			projection: "tags",
+			documents:  `[{"tags": [[1, 2], [3]]}, {"tags": [[1, 2]]}]`,
+			expected:   []interface{}{[]interface{}{float64(1), float64(2)}, []interface{}{float64(3)}},
+		},
+		{
+			name:       "nested arrays are not traversed",
+			key:        "items.sku",
+			projection: "items.sku",
+			documents:  `[{"items": [[{"sku": "nested"}], {"sku": "x"}]}]`,
+			expected:   []interface{}{"x"},
+		},
+		{
+			name:       "array index",
+			key:        "tags.1",
+			projection: "tags",
+			documents:  `[{"tags": ["a", "b"]}, {"tags": ["c"]}, {"tags": {"1": "d"}}]`,
+			expected:   []interface{}{"b", "d"},
+		},
+		{
+			name:       "numbers, types and objects",
+			key:        "v",
+			projection: "v",
+			documents:  `[{"v": 1}, {"v": 1.0}, {"v": "1"}, {"v": {"a": 1, "b": 2}}, {"v": {"b": 2, "a": 1}}, {"v": {"$date": 1700000000000}}, {"v": {"$date": 1700000000000}}, {"v": null}]`,
+			expected: []interface{}{
+				float64(1), "1", map[string]interface{}{"a": float64(1), "b": float64(2)},
+				time.UnixMilli(1700000000000).UTC(), nil,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newDistinctServer(t, tc.projection, tc.documents)
+			defer server.Close()
+
+			collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+			values, err := collection.Distinct(tc.key, nil)
+			if err != nil {
+				t.Fatalf("Distinct failed: %v", err)
+			}
+			if !reflect.DeepEqual(values, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, values)
+			}
+		})
+	}
+}
+
+func TestCollection_DistinctInvalidKey(t *testing.T) {
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase("http://localhost:1", nil, "ks1").GetCollection("coll", nil)
+	for _, key := range []string{"", "a..b", "a."} {
+		if _, err := collection.Distinct(key, nil); err == nil {
+			t.Errorf("Expected an error for key %q", key)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"stragollum/pkg/stragollum"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newInsertedIDServer answers insertOne commands with the given (raw JSON) inserted ID,
+// findOne commands with an empty result and findCollections commands with a "coll"
+// collection with ObjectId default IDs. It records the received commands.
+func newInsertedIDServer(t *testing.T, rawID string) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+	var received []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Numbers are decoded exactly, to check that large numeric IDs are sent as such
+		decoder := json.NewDecoder(r.Body)
+		decoder.UseNumber()
+		var payload map[string]any
+		if err := decoder.Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		received = append(received, payload)
+		if _, ok := payload["findOne"]; ok {
+			fmt.Fprint(w, `{"data": {"document": null}}`)
+			return
+		}
+		if _, ok := payload["findCollections"]; ok {
+			fmt.Fprint(w, `{"status": {"collections": [{"name": "coll", "options": {"defaultId": {"type": "objectId"}}}]}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status": {"insertedIds": [%s]}}`, rawID)
+	}))
+	return server, &received
+}
+
+func TestCollection_InsertOneDocumentIDKinds(t *testing.T) {
+	cases := []struct {
+		name     string
+		rawID    string
+		kind     stragollum.DocumentIDKind
+		str      string
+		filterID string
+	}{
+		{"string", `"doc1"`, stragollum.DocumentIDString, "doc1", `"doc1"`},
+		{"number", `12345678901234567`, stragollum.DocumentIDNumber, "12345678901234567", `12345678901234567`},
+		{"boolean", `true`, stragollum.DocumentIDBoolean, "true", `true`},
+		{"date", `{"$date": 1700000000000}`, stragollum.DocumentIDDate, "2023-11-14T22:13:20Z", `{"$date":1700000000000}`},
+		{"uuid", `{"$uuid": "0190a6e4-7b1c-7c3e-9f00-5a0e6f1b2c3d"}`, stragollum.DocumentIDUUID,
+			"0190a6e4-7b1c-7c3e-9f00-5a0e6f1b2c3d", `{"$uuid":"0190a6e4-7b1c-7c3e-9f00-5a0e6f1b2c3d"}`},
+		{"objectId", `{"$objectId": "65a1b2c3d4e5f60718293a4b"}`, stragollum.DocumentIDObjectID,
+			"65a1b2c3d4e5f60718293a4b", `{"$objectId":"65a1b2c3d4e5f60718293a4b"}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, received := newInsertedIDServer(t, tc.rawID)
+			defer server.Close()
+			collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+
+			id, err := collection.InsertOne(map[string]any{"a": 1})
+			if err != nil {
+				t.Fatalf("InsertOne failed: %v", err)
+			}
+			if id.Kind() != tc.kind || id.String() != tc.str {
+				t.Errorf("Unexpected ID: kind %v, %q", id.Kind(), id.String())
+			}
+
+			// The ID round-trips into a filter
+			if _, err := collection.FindOne(map[string]any{stragollum.IDField: id}); err != nil {
+				t.Fatalf("FindOne failed: %v", err)
+			}
+			filter, _ := json.Marshal((*received)[1]["findOne"].(map[string]any)["filter"])
+			if string(filter) != fmt.Sprintf(`{"_id":%s}`, tc.filterID) {
+				t.Errorf("Unexpected filter: %s", filter)
+			}
+		})
+	}
+}
+
+func TestDocumentID_Values(t *testing.T) {
+	u, err := stragollum.ParseUUID("0190a6e4-7b1c-7c3e-9f00-5a0e6f1b2c3d")
+	if err != nil {
+		t.Fatalf("ParseUUID failed: %v", err)
+	}
+	fromUUID, err := stragollum.NewDocumentID(&u)
+	if err != nil || fromUUID.Value() != u {
+		t.Fatalf("Unexpected UUID document ID: %v, %v", fromUUID, err)
+	}
+
+	// Numbers are compared by value, whatever their Go type
+	fromInt, err := stragollum.NewDocumentID(42)
+	if err != nil {
+		t.Fatalf("NewDocumentID failed: %v", err)
+	}
+	var decoded stragollum.DocumentID
+	if err := json.Unmarshal([]byte(`42`), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !fromInt.Equal(decoded) || fromInt.Kind() != stragollum.DocumentIDNumber {
+		t.Errorf("Expected %v to equal %v", fromInt, decoded)
+	}
+
+	date := time.UnixMilli(1700000000000)
+	fromDate, _ := stragollum.NewDocumentID(date)
+	encoded, err := json.Marshal(fromDate)
+	if err != nil || string(encoded) != `{"$date":1700000000000}` {
+		t.Errorf("Unexpected encoding: %s, %v", encoded, err)
+	}
+	if err := json.Unmarshal(encoded, &decoded); err != nil || !decoded.Equal(fromDate) {
+		t.Errorf("Unexpected round trip: %v, %v", decoded, err)
+	}
+
+	if err := json.Unmarshal([]byte(`null`), &decoded); err != nil || !decoded.IsZero() {
+		t.Errorf("Expected a zero ID from null, got %v, %v", decoded, err)
+	}
+	if err := json.Unmarshal([]byte(`{"a": 1}`), &decoded); err == nil {
+		t.Error("Expected an error for an object ID")
+	}
+	if _, err := stragollum.NewDocumentID([]int{1}); err == nil {
+		t.Error("Expected an error for a slice ID")
+	}
+}
+
+func TestCollection_IDGeneration(t *testing.T) {
+	t.Run("insert one", func(t *testing.T) {
+		server, received := newInsertedIDServer(t, `{"$uuid": "0190a6e4-7b1c-7c3e-9f00-5a0e6f1b2c3d"}`)
+		defer server.Close()
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil).
+			WithIDGeneration(stragollum.DefaultIDTypeUUIDv7)
+		if collection.IDGeneration() != stragollum.DefaultIDTypeUUIDv7 {
+			t.Errorf("Unexpected ID generation: %q", collection.IDGeneration())
+		}
+
+		if _, err := collection.InsertOne(map[string]any{"a": 1}); err != nil {
+			t.Fatalf("InsertOne failed: %v", err)
+		}
+		document := (*received)[0]["insertOne"].(map[string]any)["document"].(map[string]any)
+		wrapper, ok := document["_id"].(map[string]any)
+		if !ok {
+			t.Fatalf("Expected a generated _id, got %v", document)
+		}
+		u, err := stragollum.ParseUUID(fmt.Sprint(wrapper["$uuid"]))
+		if err != nil || u.Version() != 7 {
+			t.Errorf("Expected a v7 UUID, got %v (%v)", wrapper, err)
+		}
+
+		// Documents with an _id are sent as they are
+		if _, err := collection.InsertOne(map[string]any{"_id": "mine"}); err != nil {
+			t.Fatalf("InsertOne failed: %v", err)
+		}
+		document = (*received)[1]["insertOne"].(map[string]any)["document"].(map[string]any)
+		if document["_id"] != "mine" {
+			t.Errorf("Expected the given _id, got %v", document["_id"])
+		}
+	})
+
+	t.Run("structs", func(t *testing.T) {
+		var body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("Failed to read request: %v", err)
+			}
+			body = string(raw)
+			fmt.Fprint(w, `{"status": {"insertedIds": [{"$objectId": "65a1b2c3d4e5f60718293a4b"}]}}`)
+		}))
+		defer server.Close()
+		type item struct {
+			ID   *stragollum.ObjectID `json:"_id,omitempty"`
+			Name string               `json:"name"`
+		}
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil).
+			WithIDGeneration(stragollum.DefaultIDTypeObjectID)
+		id, err := collection.InsertOne(item{Name: "x"})
+		if err != nil {
+			t.Fatalf("InsertOne failed: %v", err)
+		}
+		if id.Kind() != stragollum.DocumentIDObjectID {
+			t.Errorf("Unexpected ID kind: %v", id.Kind())
+		}
+		if !strings.HasPrefix(body, `{"insertOne":{"document":{"_id":{"$objectId":"`) || !strings.HasSuffix(body, `"},"name":"x"}}}`) {
+			t.Errorf("Unexpected request: %s", body)
+		}
+	})
+
+	t.Run("insert many", func(t *testing.T) {
+		server, received, _ := newInsertManyServer(t, 0)
+		defer server.Close()
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil).
+			WithIDGeneration(stragollum.DefaultIDTypeUUID)
+		documents := []interface{}{map[string]any{"n": 1}, map[string]any{"n": 2, "fail": true}}
+		_, err := collection.InsertMany(documents, nil)
+		imErr, ok := err.(*stragollum.InsertManyError)
+		if !ok || len(imErr.Failures) != 1 {
+			t.Fatalf("Expected one failure, got %v", err)
+		}
+		sent := (*received)[0]["insertMany"].(map[string]any)["documents"].([]any)
+		for _, document := range sent {
+			if _, ok := document.(map[string]any)["_id"].(map[string]any)["$uuid"]; !ok {
+				t.Errorf("Expected a generated UUID, got %v", document)
+			}
+		}
+		// Failures report the documents as sent, with their generated _id
+		raw, ok := imErr.Failures[0].Document.(json.RawMessage)
+		if !ok {
+			t.Fatalf("Expected the failed document as sent, got %T", imErr.Failures[0].Document)
+		}
+		var failed map[string]any
+		if err := json.Unmarshal(raw, &failed); err != nil {
+			t.Fatalf("Failed to decode the failed document: %v", err)
+		}
+		if !reflect.DeepEqual(failed["_id"], sent[1].(map[string]any)["_id"]) {
+			t.Errorf("Expected the generated _id in the failed document, got %s", raw)
+		}
+	})
+
+	t.Run("default type", func(t *testing.T) {
+		server, received := newInsertedIDServer(t, `{"$objectId": "65a1b2c3d4e5f60718293a4b"}`)
+		defer server.Close()
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil).
+			WithIDGeneration()
+
+		// The defaultId type of the collection is read once, at the first insertion
+		for i := 0; i < 2; i++ {
+			if _, err := collection.InsertOne(map[string]any{"a": i}); err != nil {
+				t.Fatalf("InsertOne failed: %v", err)
+			}
+		}
+		if len(*received) != 3 || (*received)[0]["findCollections"] == nil {
+			t.Fatalf("Expected the definition to be read once, got %v", *received)
+		}
+		if collection.IDGeneration() != stragollum.DefaultIDTypeObjectID {
+			t.Errorf("Unexpected ID generation: %q", collection.IDGeneration())
+		}
+		for _, request := range (*received)[1:] {
+			document := request["insertOne"].(map[string]any)["document"].(map[string]any)
+			if _, ok := document["_id"].(map[string]any)["$objectId"]; !ok {
+				t.Errorf("Expected a generated ObjectId, got %v", document)
+			}
+		}
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase("http://localhost:1", nil, "ks1").GetCollection("coll", nil).
+			WithIDGeneration("sequence")
+		if _, err := collection.InsertOne(map[string]any{"a": 1}); err == nil || !strings.Contains(err.Error(), "sequence") {
+			t.Errorf("Expected an unsupported type error, got %v", err)
+		}
+	})
+}
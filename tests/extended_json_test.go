@@ -0,0 +1,220 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"testing"
+	"time"
+)
+
+func TestUUID(t *testing.T) {
+	generators := map[int]func() (stragollum.UUID, error){
+		1: stragollum.NewUUIDv1,
+		4: stragollum.NewUUIDv4,
+		6: stragollum.NewUUIDv6,
+		7: stragollum.NewUUIDv7,
+	}
+	for version, generate := range generators {
+		u, err := generate()
+		if err != nil {
+			t.Fatalf("Generating a v%d UUID failed: %v", version, err)
+		}
+		if u.Version() != version {
+			t.Errorf("Expected version %d, got %d (%s)", version, u.Version(), u)
+		}
+		parsed, err := stragollum.ParseUUID(u.String())
+		if err != nil || parsed != u {
+			t.Errorf("Round trip of %s failed: %v, %v", u, parsed, err)
+		}
+	}
+
+	first, _ := stragollum.NewUUIDv7()
+	time.Sleep(2 * time.Millisecond)
+	second, _ := stragollum.NewUUIDv7()
+	if first.String() >= second.String() {
+		t.Errorf("Expected time-ordered v7 UUIDs, got %s then %s", first, second)
+	}
+
+	if _, err := stragollum.ParseUUID("not-a-uuid"); err == nil {
+		t.Errorf("Expected an error for an invalid UUID")
+	}
+
+	u, _ := stragollum.ParseUUID("0192d2b7-0d4e-7b3a-9f2e-0123456789ab")
+	encoded, _ := json.Marshal(u)
+	if string(encoded) != `{"$uuid":"0192d2b7-0d4e-7b3a-9f2e-0123456789ab"}` {
+		t.Errorf("Unexpected encoding: %s", encoded)
+	}
+	for _, representation := range []string{string(encoded), `"0192d2b7-0d4e-7b3a-9f2e-0123456789ab"`} {
+		var decoded stragollum.UUID
+		if err := json.Unmarshal([]byte(representation), &decoded); err != nil || decoded != u {
+			t.Errorf("Unexpected decoding of %s: %v, %v", representation, decoded, err)
+		}
+	}
+}
+
+func TestObjectID(t *testing.T) {
+	first := stragollum.NewObjectID()
+	second := stragollum.NewObjectID()
+	if first == second {
+		t.Errorf("Expected distinct ObjectIDs, got %s twice", first)
+	}
+	if time.Since(first.Timestamp()) > time.Minute {
+		t.Errorf("Unexpected timestamp: %v", first.Timestamp())
+	}
+	parsed, err := stragollum.ParseObjectID(first.Hex())
+	if err != nil || parsed != first {
+		t.Errorf("Round trip of %s failed: %v, %v", first, parsed, err)
+	}
+	encoded, _ := json.Marshal(first)
+	var decoded stragollum.ObjectID
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded != first {
+		t.Errorf("Unexpected decoding of %s: %v, %v", encoded, decoded, err)
+	}
+	if _, err := stragollum.ParseObjectID("xyz"); err == nil {
+		t.Errorf("Expected an error for an invalid ObjectID")
+	}
+}
+
+func TestExtendedJSON_Encoding(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.Write([]byte(`{"status": {"insertedIds": ["x"]}}`))
+	}))
+	defer server.Close()
+
+	type event struct {
+		ID      stragollum.UUID     `dataapi:"id"`
+		Owner   stragollum.ObjectID `json:"owner"`
+		At      time.Time           `json:"at"`
+		Expires *time.Time          `json:"expires,omitempty"`
+	}
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	id, _ := stragollum.ParseUUID("0192d2b7-0d4e-7b3a-9f2e-0123456789ab")
+	owner, _ := stragollum.ParseObjectID("65f1a2b3c4d5e6f708192a3b")
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("events", nil)
+	if _, err := collection.InsertOne(event{ID: id, Owner: owner, At: at, Expires: &at}); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	expected := `{"insertOne":{"document":{"_id":{"$uuid":"0192d2b7-0d4e-7b3a-9f2e-0123456789ab"},"owner":{"$objectId":"65f1a2b3c4d5e6f708192a3b"},"at":{"$date":1714564800000},"expires":{"$date":1714564800000}}}}`
+	if received != expected {
+		t.Errorf("Unexpected payload:\n got %s\nwant %s", received, expected)
+	}
+
+	if _, err := collection.InsertOne(map[string]any{"at": at, "tags": []any{id}}); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	expected = `{"insertOne":{"document":{"at":{"$date":1714564800000},"tags":[{"$uuid":"0192d2b7-0d4e-7b3a-9f2e-0123456789ab"}]}}}`
+	if received != expected {
+		t.Errorf("Unexpected payload:\n got %s\nwant %s", received, expected)
+	}
+	// Everything but the values the Data API serializes in its own way follows encoding/json
+	type note struct {
+		Count   int            `json:"count,string"`
+		Size    json.Number    `json:"size"`
+		Extra   any            `json:"extra,omitempty"`
+		Meta    map[string]any `json:"meta"`
+		Skipped any            `json:"skipped,omitempty"`
+		Custom  customTime     `json:"custom"`
+	}
+	document := note{Count: 3, Size: "1.50", Extra: at, Meta: map[string]any{"at": &at}, Custom: customTime{at}}
+	if _, err := collection.InsertOne(document); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	expected = `{"insertOne":{"document":{"count":"3","size":1.50,"extra":{"$date":1714564800000},` +
+		`"meta":{"at":{"$date":1714564800000}},"custom":"2024-05-01"}}}`
+	if received != expected {
+		t.Errorf("Unexpected payload:\n got %s\nwant %s", received, expected)
+	}
+}
+
+// customTime is a time with its own JSON representation.
+type customTime struct {
+	time.Time
+}
+
+func (c customTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Format("2006-01-02"))
+}
+
+func TestExtendedJSON_Decoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"document": {
+			"_id": {"$uuid": "0192d2b7-0d4e-7b3a-9f2e-0123456789ab"},
+			"owner": {"$objectId": "65f1a2b3c4d5e6f708192a3b"},
+			"at": {"$date": 1714564800000},
+			"history": [{"at": {"$date": 1714564800001}}],
+			"big": 9007199254740993,
+			"$vector": {"$binary": "P4AAAA=="}
+		}}}`))
+	}))
+	defer server.Close()
+
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	document, err := db.GetCollection("events", nil).FindOne(nil)
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if id, ok := document["_id"].(stragollum.UUID); !ok || id.String() != "0192d2b7-0d4e-7b3a-9f2e-0123456789ab" {
+		t.Errorf("Unexpected _id: %#v", document["_id"])
+	}
+	if owner, ok := document["owner"].(stragollum.ObjectID); !ok || owner.Hex() != "65f1a2b3c4d5e6f708192a3b" {
+		t.Errorf("Unexpected owner: %#v", document["owner"])
+	}
+	if date, ok := document["at"].(time.Time); !ok || !date.Equal(at) {
+		t.Errorf("Unexpected at: %#v", document["at"])
+	}
+	nested := document["history"].([]interface{})[0].(map[string]interface{})
+	if date, ok := nested["at"].(time.Time); !ok || !date.Equal(at.Add(time.Millisecond)) {
+		t.Errorf("Unexpected nested date: %#v", nested["at"])
+	}
+	if vector, ok := document["$vector"].(stragollum.DataAPIVector); !ok || len(vector) != 1 || vector[0] != 1 {
+		t.Errorf("Unexpected vector: %#v", document["$vector"])
+	}
+
+	type event struct {
+		ID      stragollum.UUID     `dataapi:"id"`
+		Owner   stragollum.ObjectID `json:"owner"`
+		At      time.Time           `json:"at"`
+		History []struct {
+			At *time.Time `json:"at"`
+		} `json:"history"`
+		Big int64 `json:"big"`
+	}
+	typed, err := stragollum.GetTypedCollection[event](db, "events", nil).FindOne(nil)
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if typed.ID.String() != "0192d2b7-0d4e-7b3a-9f2e-0123456789ab" || typed.Owner.Hex() != "65f1a2b3c4d5e6f708192a3b" {
+		t.Errorf("Unexpected IDs: %+v", typed)
+	}
+	if !typed.At.Equal(at) || len(typed.History) != 1 || !typed.History[0].At.Equal(at.Add(time.Millisecond)) {
+		t.Errorf("Unexpected dates: %+v", typed)
+	}
+	if typed.Big != 9007199254740993 {
+		t.Errorf("Expected an exact int64, got %d", typed.Big)
+	}
+
+	// Only the dates landing on time.Time fields are converted
+	type looseEvent struct {
+		At      time.Time                `json:"at"`
+		History []map[string]interface{} `json:"history"`
+	}
+	loose, err := stragollum.GetTypedCollection[looseEvent](db, "events", nil).FindOne(nil)
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if !loose.At.Equal(at) {
+		t.Errorf("Unexpected at: %v", loose.At)
+	}
+	if date, ok := loose.History[0]["at"].(map[string]interface{}); !ok || date["$date"] == nil {
+		t.Errorf("Expected the date wrapper to be kept, got %#v", loose.History[0]["at"])
+	}
+}
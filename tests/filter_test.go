@@ -0,0 +1,119 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"stragollum/pkg/stragollum"
+	"stragollum/pkg/stragollum/filter"
+	"testing"
+)
+
+func TestFilter_JSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filter   filter.Filter
+		expected string
+	}{
+		{"eq", filter.Eq("a", 1), `{"a":{"$eq":1}}`},
+		{"ne", filter.Ne("a", "x"), `{"a":{"$ne":"x"}}`},
+		{"in", filter.In("a", 1, "two"), `{"a":{"$in":[1,"two"]}}`},
+		{"nin empty", filter.Nin("a"), `{"a":{"$nin":[]}}`},
+		{"gt", filter.Gt("n", 1.5), `{"n":{"$gt":1.5}}`},
+		{"gte", filter.Gte("n", 2), `{"n":{"$gte":2}}`},
+		{"lt", filter.Lt("n", 3), `{"n":{"$lt":3}}`},
+		{"lte", filter.Lte("n", 4), `{"n":{"$lte":4}}`},
+		{"exists", filter.Exists("opt"), `{"opt":{"$exists":true}}`},
+		{"all", filter.All("tags", "a", "b"), `{"tags":{"$all":["a","b"]}}`},
+		{"size", filter.Size("tags", 2), `{"tags":{"$size":2}}`},
+		{"match", filter.Match("green tea"), `{"$lexical":{"$match":"green tea"}}`},
+		{"not", filter.Not(filter.Eq("a", 1)), `{"$not":{"a":{"$eq":1}}}`},
+		{
+			"and/or with raw maps",
+			filter.And(map[string]any{"kind": "k"}, filter.Or(filter.Lt("n", 0), filter.Gt("n", 10))),
+			`{"$and":[{"kind":"k"},{"$or":[{"n":{"$lt":0}},{"n":{"$gt":10}}]}]}`,
+		},
+		{
+			"merge",
+			filter.Merge(filter.Gte("age", 18), filter.Lt("age", 65), filter.Eq("city", "Rome"), map[string]any{"meta": map[string]any{"x": 1}}),
+			`{"age":{"$gte":18,"$lt":65},"city":{"$eq":"Rome"},"meta":{"x":1}}`,
+		},
+		{
+			"merge same operator",
+			filter.Merge(filter.Gt("x", 10), filter.Gt("x", 1)),
+			`{"$and":[{"x":{"$gt":1}}],"x":{"$gt":10}}`,
+		},
+		{
+			"merge equalities",
+			filter.Merge(filter.Eq("x", 1), filter.Eq("x", 2), map[string]any{"y": "a"}, map[string]any{"y": "b"}),
+			`{"$and":[{"x":{"$eq":2}},{"y":"b"}],"x":{"$eq":1},"y":"a"}`,
+		},
+		{
+			"merge or",
+			filter.Merge(
+				filter.Or(filter.Eq("tenant", "a"), filter.Eq("tenant", "b")),
+				filter.Or(filter.Eq("owner", "me"), filter.Exists("public")),
+			),
+			`{"$and":[{"$or":[{"owner":{"$eq":"me"}},{"public":{"$exists":true}}]}],"$or":[{"tenant":{"$eq":"a"}},{"tenant":{"$eq":"b"}}]}`,
+		},
+		{
+			"merge not",
+			filter.Merge(filter.Not(filter.Eq("a", 1)), filter.Not(filter.Eq("b", 2))),
+			`{"$and":[{"$not":{"b":{"$eq":2}}}],"$not":{"a":{"$eq":1}}}`,
+		},
+		{
+			"merge and lists",
+			filter.Merge(
+				filter.And(filter.Eq("a", 1), filter.Eq("b", 2)),
+				filter.Eq("c", 3),
+				map[string]any{"$and": []any{map[string]any{"d": 4}}},
+			),
+			`{"$and":[{"a":{"$eq":1}},{"b":{"$eq":2}},{"d":4}],"c":{"$eq":3}}`,
+		},
+		{"in spread", filter.In("x", []any{"a", "b"}...), `{"x":{"$in":["a","b"]}}`},
+		{"nin spread", filter.Nin("x", []any{1, 2}...), `{"x":{"$nin":[1,2]}}`},
+		{"all spread", filter.All("tags", []any{"a", 1}...), `{"tags":{"$all":["a",1]}}`},
+		{"in nil spread", filter.In("x", []any(nil)...), `{"x":{"$in":[]}}`},
+		{"in slice value", filter.In("x", []string{"a", "b"}), `{"x":{"$in":[["a","b"]]}}`},
+		{"in slices", filter.In("x", []string{"a"}, []string{"b"}), `{"x":{"$in":[["a"],["b"]]}}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := json.Marshal(tc.filter)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if string(encoded) != tc.expected {
+				t.Errorf("Unexpected JSON:\n got %s\nwant %s", encoded, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFilter_Collection(t *testing.T) {
+	var received []map[string]any
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		received = append(received, command)
+	}, `{"data": {"document": null}, "status": {"count": 0}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	if _, err := collection.FindOne(filter.And(filter.Eq("a", 1), filter.Exists("b"))); err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	var noFilter filter.Filter
+	if _, err := collection.CountDocuments(noFilter, 10); err != nil {
+		t.Fatalf("CountDocuments failed: %v", err)
+	}
+	if _, err := collection.DeleteMany(noFilter); err != stragollum.ErrEmptyFilter {
+		t.Errorf("Expected ErrEmptyFilter for a nil Filter, got %v", err)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(received))
+	}
+	if and, ok := received[0]["filter"].(map[string]any)["$and"].([]any); !ok || len(and) != 2 {
+		t.Errorf("Unexpected filter: %v", received[0]["filter"])
+	}
+	if f, ok := received[1]["filter"].(map[string]any); !ok || len(f) != 0 {
+		t.Errorf("Expected an empty filter object, got %v", received[1]["filter"])
+	}
+}
@@ -0,0 +1,171 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+// newCommandServer decodes each command, hands it to check (with the command name),
+// then replies with the given body.
+func newCommandServer(t *testing.T, check func(name string, command map[string]any), body string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]map[string]any
+		// t.Fatalf must not be called outside the test goroutine: report and fail the request
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(payload) != 1 {
+			t.Errorf("Expected a single command, got %v", payload)
+			http.Error(w, "expected a single command", http.StatusBadRequest)
+			return
+		}
+		for name, command := range payload {
+			check(name, command)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestCollection_FindOneAndUpdate(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if name != "findOneAndUpdate" {
+			t.Errorf("Unexpected command %q", name)
+		}
+		if command["filter"].(map[string]any)["status"] != "pending" {
+			t.Errorf("Unexpected filter: %v", command["filter"])
+		}
+		if command["update"].(map[string]any)["$set"].(map[string]any)["status"] != "claimed" {
+			t.Errorf("Unexpected update: %v", command["update"])
+		}
+		if command["sort"].(map[string]any)["priority"] != float64(-1) {
+			t.Errorf("Unexpected sort: %v", command["sort"])
+		}
+		if command["projection"].(map[string]any)["payload"] != float64(1) {
+			t.Errorf("Unexpected projection: %v", command["projection"])
+		}
+		options := command["options"].(map[string]any)
+		if options["returnDocument"] != "after" || options["upsert"] != true {
+			t.Errorf("Unexpected options: %v", options)
+		}
+	}, `{"data": {"document": {"_id": "job1", "payload": "p"}}, "status": {"matchedCount": 1, "modifiedCount": 1}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("jobs", nil)
+	doc, result, err := collection.FindOneAndUpdate(
+		map[string]any{"status": "pending"},
+		stragollum.NewUpdate().Set("status", "claimed"),
+		&stragollum.FindOneAndUpdateOptions{
+			ReturnDocument: stragollum.ReturnDocumentAfter,
+			Upsert:         true,
+			Sort:           map[string]any{"priority": -1},
+			Projection:     map[string]any{"payload": 1},
+		},
+	)
+	if err != nil {
+		t.Fatalf("FindOneAndUpdate failed: %v", err)
+	}
+	if doc["_id"] != "job1" {
+		t.Errorf("Unexpected document: %v", doc)
+	}
+	if result.MatchedCount != 1 || result.ModifiedCount != 1 || result.UpsertedID != nil {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestCollection_FindOneAndUpdateNoMatch(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if _, ok := command["options"]; ok {
+			t.Errorf("No options expected, got %v", command["options"])
+		}
+	}, `{"data": {"document": null}, "status": {"matchedCount": 0, "modifiedCount": 0}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("jobs", nil)
+	doc, result, err := collection.FindOneAndUpdate(map[string]any{}, stragollum.NewUpdate().Set("a", 1), nil)
+	if err != nil {
+		t.Fatalf("FindOneAndUpdate failed: %v", err)
+	}
+	if doc != nil || result.MatchedCount != 0 {
+		t.Errorf("Expected no document and no match, got %v, %+v", doc, result)
+	}
+}
+
+func TestCollection_FindOneAndReplace(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if name != "findOneAndReplace" {
+			t.Errorf("Unexpected command %q", name)
+		}
+		if command["replacement"].(map[string]any)["v"] != float64(2) {
+			t.Errorf("Unexpected replacement: %v", command["replacement"])
+		}
+		if command["options"].(map[string]any)["returnDocument"] != "before" {
+			t.Errorf("Unexpected options: %v", command["options"])
+		}
+	}, `{"data": {"document": {"_id": "d", "v": 1}}, "status": {"matchedCount": 1, "modifiedCount": 1}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	doc, result, err := collection.FindOneAndReplace(
+		map[string]any{"_id": "d"},
+		map[string]any{"v": 2},
+		&stragollum.FindOneAndReplaceOptions{ReturnDocument: stragollum.ReturnDocumentBefore},
+	)
+	if err != nil {
+		t.Fatalf("FindOneAndReplace failed: %v", err)
+	}
+	if doc["v"] != float64(1) || result.ModifiedCount != 1 {
+		t.Errorf("Unexpected outcome: %v, %+v", doc, result)
+	}
+}
+
+func TestCollection_FindOneAndDelete(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if name != "findOneAndDelete" {
+			t.Errorf("Unexpected command %q", name)
+		}
+		if command["sort"].(map[string]any)["ts"] != float64(1) {
+			t.Errorf("Unexpected sort: %v", command["sort"])
+		}
+	}, `{"data": {"document": {"_id": "oldest"}}, "status": {"deletedCount": 1}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	doc, result, err := collection.FindOneAndDelete(map[string]any{}, &stragollum.FindOneAndDeleteOptions{
+		Sort: map[string]any{"ts": 1},
+	})
+	if err != nil {
+		t.Fatalf("FindOneAndDelete failed: %v", err)
+	}
+	if doc["_id"] != "oldest" || result.DeletedCount != 1 {
+		t.Errorf("Unexpected outcome: %v, %+v", doc, result)
+	}
+}
+
+func TestCollection_ReplaceOne(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if name != "findOneAndReplace" {
+			t.Errorf("Unexpected command %q", name)
+		}
+		if command["options"].(map[string]any)["upsert"] != true {
+			t.Errorf("Unexpected options: %v", command["options"])
+		}
+	}, `{"data": {"document": null}, "status": {"matchedCount": 0, "modifiedCount": 0, "upsertedId": "u"}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	result, err := collection.ReplaceOne(map[string]any{"_id": "u"}, map[string]any{"v": 1}, &stragollum.ReplaceOneOptions{Upsert: true})
+	if err != nil {
+		t.Fatalf("ReplaceOne failed: %v", err)
+	}
+	if result.UpsertedCount != 1 || result.UpsertedID != "u" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,114 @@
+package stragollum_test
+
+import (
+	"reflect"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+func TestCollection_FindAndRerank(t *testing.T) {
+	calls := 0
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		calls++
+		if name != "findAndRerank" {
+			t.Errorf("Unexpected command %q", name)
+		}
+		hybrid := command["sort"].(map[string]any)["$hybrid"].(map[string]any)
+		if hybrid["$vectorize"] != "cats" || hybrid["$lexical"] != "cat feline" {
+			t.Errorf("Unexpected sort: %v", command["sort"])
+		}
+		expectedOptions := map[string]any{
+			"limit":             float64(2),
+			"hybridLimits":      map[string]any{"$vector": float64(20), "$lexical": float64(10)},
+			"rerankOn":          "body",
+			"rerankQuery":       "about cats",
+			"includeScores":     true,
+			"includeSortVector": true,
+		}
+		if !reflect.DeepEqual(command["options"], expectedOptions) {
+			t.Errorf("Unexpected options: %v", command["options"])
+		}
+	}, `{
+		"data": {"documents": [{"_id": "d1", "body": "cat"}, {"_id": "d2", "body": "kitten"}], "nextPageState": null},
+		"status": {
+			"documentResponses": [
+				{"scores": {"$rerank": 0.9, "$vector": 0.8, "$lexical": 0.5}},
+				{"scores": {"$rerank": 0.4, "$vector": 0.7}}
+			],
+			"sortVector": [0.1, 0.2]
+		}
+	}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	cursor := collection.FindAndRerank(nil, &stragollum.FindAndRerankOptions{
+		Sort:              stragollum.HybridVectorizeSort("cats", "cat feline"),
+		Limit:             2,
+		HybridLimits:      map[string]int{"$vector": 20, "$lexical": 10},
+		RerankOn:          "body",
+		RerankQuery:       "about cats",
+		IncludeScores:     true,
+		IncludeSortVector: true,
+	})
+	if calls != 0 {
+		t.Errorf("No request expected before using the cursor")
+	}
+
+	sortVector, err := cursor.SortVector()
+	if err != nil || !reflect.DeepEqual(sortVector, []float32{0.1, 0.2}) {
+		t.Errorf("Unexpected sort vector: %v, %v", sortVector, err)
+	}
+	results, err := cursor.All()
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if len(results) != 2 || calls != 1 {
+		t.Fatalf("Expected 2 results from a single request, got %d from %d", len(results), calls)
+	}
+	if results[0].Document["_id"] != "d1" || results[0].Scores[stragollum.RerankScore] != 0.9 || results[0].Scores["$lexical"] != 0.5 {
+		t.Errorf("Unexpected first result: %+v", results[0])
+	}
+	if results[1].Document["body"] != "kitten" || results[1].Scores["$vector"] != 0.7 {
+		t.Errorf("Unexpected second result: %+v", results[1])
+	}
+}
+
+func TestTypedCollection_FindAndRerank(t *testing.T) {
+	type review struct {
+		ID   string `dataapi:"id"`
+		Body string `json:"body"`
+	}
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if command["sort"].(map[string]any)["$hybrid"] != "great food" {
+			t.Errorf("Unexpected sort: %v", command["sort"])
+		}
+		if _, ok := command["options"]; ok {
+			t.Errorf("No options expected, got %v", command["options"])
+		}
+	}, `{"data": {"documents": [{"_id": "r1", "body": "great"}]}, "status": {}}`)
+	defer server.Close()
+
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+	cursor := stragollum.GetTypedCollection[review](db, "reviews", nil).FindAndRerank(nil, &stragollum.FindAndRerankOptions{
+		Sort: stragollum.HybridSort("great food"),
+	})
+	defer cursor.Close()
+	if !cursor.Next() {
+		t.Fatalf("Expected a result, got error %v", cursor.Err())
+	}
+	result, err := cursor.Current()
+	if err != nil || result.Document.ID != "r1" || result.Document.Body != "great" || result.Scores != nil {
+		t.Errorf("Unexpected result: %+v, %v", result, err)
+	}
+	if cursor.Next() {
+		t.Errorf("Expected the results to be exhausted")
+	}
+	if _, err := cursor.Current(); err != stragollum.ErrNoCurrentDocument {
+		t.Errorf("Expected ErrNoCurrentDocument, got %v", err)
+	}
+
+	missingSort := stragollum.GetTypedCollection[review](db, "reviews", nil).FindAndRerank(nil, nil)
+	if missingSort.Next() || missingSort.Err() == nil {
+		t.Errorf("Expected an error for a missing hybrid sort")
+	}
+}
@@ -0,0 +1,267 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+// newPagingServer serves a "find" command over three pages of two documents each,
+// recording the received commands.
+func newPagingServer(t *testing.T, received *[]map[string]any) *httptest.Server {
+	t.Helper()
+	pages := map[string]string{
+		"":   `{"data": {"documents": [{"_id": "d1", "n": 1}, {"_id": "d2", "n": 2}], "nextPageState": "p2"}, "status": {"sortVector": [0.1, 0.2]}}`,
+		"p2": `{"data": {"documents": [{"_id": "d3", "n": 3}, {"_id": "d4", "n": 4}], "nextPageState": "p3"}}`,
+		"p3": `{"data": {"documents": [{"_id": "d5", "n": 5}, {"_id": "d6", "n": 6}], "nextPageState": null}}`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		*received = append(*received, payload)
+		find, ok := payload["find"].(map[string]any)
+		if !ok {
+			t.Fatalf("Payload missing find: %v", payload)
+		}
+		pageState := ""
+		if options, ok := find["options"].(map[string]any); ok {
+			if ps, ok := options["pageState"].(string); ok {
+				pageState = ps
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, pages[pageState])
+	}))
+}
+
+func TestCollection_Find(t *testing.T) {
+	var received []map[string]any
+	server := newPagingServer(t, &received)
+	defer server.Close()
+
+	client := stragollum.NewDataAPIClient(nil, nil)
+	collection := client.GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+
+	t.Run("NextAndDecode", func(t *testing.T) {
+		received = nil
+		cursor := collection.Find(map[string]any{"tag": "x"}, &stragollum.FindOptions{
+			Sort:              map[string]any{"n": 1},
+			Projection:        map[string]any{"n": 1},
+			Skip:              1,
+			IncludeSimilarity: true,
+			IncludeSortVector: true,
+		})
+		defer cursor.Close()
+
+		if len(received) != 0 {
+			t.Fatalf("Find should be lazy, but %d requests were issued", len(received))
+		}
+
+		var ids []string
+		for cursor.Next() {
+			var doc struct {
+				ID string `json:"_id"`
+				N  int    `json:"n"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			ids = append(ids, doc.ID)
+			if len(ids) == 1 && cursor.Buffered() != 1 {
+				t.Errorf("Expected 1 buffered document, got %d", cursor.Buffered())
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			t.Fatalf("Cursor error: %v", err)
+		}
+		if len(ids) != 6 || ids[0] != "d1" || ids[5] != "d6" {
+			t.Errorf("Unexpected ids: %v", ids)
+		}
+		if cursor.Consumed() != 6 {
+			t.Errorf("Expected 6 consumed documents, got %d", cursor.Consumed())
+		}
+		if len(received) != 3 {
+			t.Fatalf("Expected 3 page requests, got %d", len(received))
+		}
+
+		first := received[0]["find"].(map[string]any)
+		if first["filter"].(map[string]any)["tag"] != "x" {
+			t.Errorf("Unexpected filter: %v", first["filter"])
+		}
+		if first["sort"].(map[string]any)["n"] != float64(1) || first["projection"].(map[string]any)["n"] != float64(1) {
+			t.Errorf("Unexpected sort/projection: %v", first)
+		}
+		options := first["options"].(map[string]any)
+		if options["skip"] != float64(1) || options["includeSimilarity"] != true || options["includeSortVector"] != true {
+			t.Errorf("Unexpected options: %v", options)
+		}
+		if _, ok := options["pageState"]; ok {
+			t.Errorf("First request should not carry a pageState: %v", options)
+		}
+		if received[2]["find"].(map[string]any)["options"].(map[string]any)["pageState"] != "p3" {
+			t.Errorf("Third request should carry pageState p3: %v", received[2])
+		}
+
+		if cursor.Next() {
+			t.Error("Next should return false on an exhausted cursor")
+		}
+	})
+
+	t.Run("All", func(t *testing.T) {
+		var docs []map[string]any
+		if err := collection.Find(nil, nil).All(&docs); err != nil {
+			t.Fatalf("All failed: %v", err)
+		}
+		if len(docs) != 6 || docs[2]["_id"] != "d3" {
+			t.Errorf("Unexpected documents: %v", docs)
+		}
+
+		var notASlice map[string]any
+		if err := collection.Find(nil, nil).All(&notASlice); err == nil {
+			t.Error("Expected error for a non-slice All target")
+		}
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		received = nil
+		var docs []map[string]any
+		if err := collection.Find(nil, &stragollum.FindOptions{Limit: 3}).All(&docs); err != nil {
+			t.Fatalf("All failed: %v", err)
+		}
+		if len(docs) != 3 {
+			t.Errorf("Expected 3 documents, got %d", len(docs))
+		}
+		if len(received) != 2 {
+			t.Errorf("Expected 2 page requests, got %d", len(received))
+		}
+		if received[0]["find"].(map[string]any)["options"].(map[string]any)["limit"] != float64(3) {
+			t.Errorf("Expected limit in options: %v", received[0])
+		}
+	})
+
+	t.Run("Iter", func(t *testing.T) {
+		count := 0
+		collection.Find(nil, nil).Iter()(func(doc map[string]any, err error) bool {
+			if err != nil {
+				t.Fatalf("Iteration error: %v", err)
+			}
+			count++
+			return count < 4
+		})
+		if count != 4 {
+			t.Errorf("Expected iteration to stop after 4 documents, got %d", count)
+		}
+	})
+
+	t.Run("SortVector", func(t *testing.T) {
+		received = nil
+		cursor := collection.Find(nil, &stragollum.FindOptions{IncludeSortVector: true})
+		sortVector, err := cursor.SortVector()
+		if err != nil {
+			t.Fatalf("SortVector failed: %v", err)
+		}
+		if len(sortVector) != 2 || sortVector[1] != float32(0.2) {
+			t.Errorf("Unexpected sort vector: %v", sortVector)
+		}
+		if cursor.Buffered() != 2 || cursor.Consumed() != 0 {
+			t.Errorf("Expected 2 buffered and 0 consumed, got %d and %d", cursor.Buffered(), cursor.Consumed())
+		}
+		if !cursor.Next() {
+			t.Fatal("Expected a document after SortVector")
+		}
+		doc, _ := cursor.Document()
+		if doc["_id"] != "d1" {
+			t.Errorf("Unexpected first document: %v", doc)
+		}
+		if len(received) != 1 {
+			t.Errorf("Expected a single request, got %d", len(received))
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		cursor := collection.Find(nil, nil)
+		if err := cursor.Decode(&map[string]any{}); err != stragollum.ErrNoCurrentDocument {
+			t.Errorf("Expected ErrNoCurrentDocument, got %v", err)
+		}
+		cursor.Next()
+		cursor.Close()
+		if cursor.Next() {
+			t.Error("Next should return false on a closed cursor")
+		}
+		if err := cursor.Decode(&map[string]any{}); err != stragollum.ErrCursorClosed {
+			t.Errorf("Expected ErrCursorClosed, got %v", err)
+		}
+	})
+}
+
+func TestCollection_FindErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			fmt.Fprint(w, `{"data": {"documents": [{"_id": "d1"}], "nextPageState": "p2"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"errors": [{"errorCode": "SOMETHING_BAD", "message": "boom"}]}`)
+	}))
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	var docs []map[string]any
+	err := collection.Find(nil, nil).All(&docs)
+	if err == nil {
+		t.Fatal("Expected error from the second page, got nil")
+	}
+	if len(docs) != 1 {
+		t.Errorf("Expected the first page document to be decoded, got %v", docs)
+	}
+}
+
+func TestCollection_FindOneWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		findOne := payload["findOne"].(map[string]any)
+		if findOne["sort"].(map[string]any)["n"] != float64(-1) {
+			t.Errorf("Unexpected sort: %v", findOne["sort"])
+		}
+		if findOne["projection"].(map[string]any)["n"] != float64(1) {
+			t.Errorf("Unexpected projection: %v", findOne["projection"])
+		}
+		if findOne["options"].(map[string]any)["includeSimilarity"] != true {
+			t.Errorf("Unexpected options: %v", findOne["options"])
+		}
+		fmt.Fprint(w, `{"data": {"document": {"_id": "d6", "n": 6}}}`)
+	}))
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	doc, err := collection.FindOne(map[string]any{}, &stragollum.FindOneOptions{
+		Sort:              map[string]any{"n": -1},
+		Projection:        map[string]any{"n": 1},
+		IncludeSimilarity: true,
+	})
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if doc["_id"] != "d6" {
+		t.Errorf("Unexpected document: %v", doc)
+	}
+
+	notFoundServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"document": null}}`)
+	}))
+	defer notFoundServer.Close()
+	doc, err = stragollum.NewDataAPIClient(nil, nil).GetDatabase(notFoundServer.URL, nil, "ks1").
+		GetCollection("coll", nil).FindOne(nil)
+	if err != nil || doc != nil {
+		t.Errorf("Expected nil document and no error, got %v, %v", doc, err)
+	}
+}
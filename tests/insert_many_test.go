@@ -0,0 +1,242 @@
+package stragollum_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newInsertManyServer accepts insertMany commands, failing documents having "fail": true.
+// It records the received commands and the maximum number of requests in flight.
+func newInsertManyServer(t *testing.T, delay time.Duration) (*httptest.Server, *[]map[string]any, *int32) {
+	t.Helper()
+	var mu sync.Mutex
+	var received []map[string]any
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+		time.Sleep(delay)
+
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		mu.Lock()
+		received = append(received, payload)
+		mu.Unlock()
+
+		insertMany := payload["insertMany"].(map[string]any)
+		ordered := insertMany["options"].(map[string]any)["ordered"] == true
+		var responses, apiErrors []string
+		failed := false
+		for _, d := range insertMany["documents"].([]any) {
+			doc := d.(map[string]any)
+			switch {
+			case failed && ordered:
+				responses = append(responses, fmt.Sprintf(`{"_id": "%s", "status": "SKIPPED"}`, doc["_id"]))
+			case doc["fail"] == true:
+				failed = true
+				responses = append(responses, fmt.Sprintf(`{"_id": "%s", "status": "ERROR", "errorsIdx": %d}`, doc["_id"], len(apiErrors)))
+				apiErrors = append(apiErrors, fmt.Sprintf(`{"errorCode": "DOCUMENT_ALREADY_EXISTS", "message": "duplicate %s"}`, doc["_id"]))
+			default:
+				responses = append(responses, fmt.Sprintf(`{"_id": "%s", "status": "OK"}`, doc["_id"]))
+			}
+		}
+		body := fmt.Sprintf(`{"status": {"documentResponses": [%s]}`, strings.Join(responses, ","))
+		if len(apiErrors) > 0 {
+			body += fmt.Sprintf(`, "errors": [%s]`, strings.Join(apiErrors, ","))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body+"}")
+	}))
+	return server, &received, &maxInFlight
+}
+
+func makeDocuments(n int, failing ...int) []interface{} {
+	documents := make([]interface{}, n)
+	for i := range documents {
+		documents[i] = map[string]any{"_id": fmt.Sprintf("doc%03d", i)}
+	}
+	for _, i := range failing {
+		documents[i].(map[string]any)["fail"] = true
+	}
+	return documents
+}
+
+func TestCollection_InsertMany(t *testing.T) {
+	t.Run("ChunkingAndOrder", func(t *testing.T) {
+		server, received, _ := newInsertManyServer(t, 0)
+		defer server.Close()
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+
+		ids, err := collection.InsertMany(makeDocuments(120), nil)
+		if err != nil {
+			t.Fatalf("InsertMany failed: %v", err)
+		}
+		if len(ids) != 120 {
+			t.Fatalf("Expected 120 ids, got %d", len(ids))
+		}
+		for i, id := range ids {
+			if id.String() != fmt.Sprintf("doc%03d", i) {
+				t.Fatalf("Unexpected id at %d: %s", i, id)
+			}
+		}
+		if len(*received) != 3 {
+			t.Fatalf("Expected 3 requests with the default chunk size, got %d", len(*received))
+		}
+		options := (*received)[0]["insertMany"].(map[string]any)["options"].(map[string]any)
+		if options["ordered"] != false || options["returnDocumentResponses"] != true {
+			t.Errorf("Unexpected options: %v", options)
+		}
+	})
+
+	t.Run("CustomChunkSize", func(t *testing.T) {
+		server, received, _ := newInsertManyServer(t, 0)
+		defer server.Close()
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+
+		ids, err := collection.InsertMany(makeDocuments(25), &stragollum.InsertManyOptions{ChunkSize: 10, Ordered: true})
+		if err != nil {
+			t.Fatalf("InsertMany failed: %v", err)
+		}
+		if len(ids) != 25 || len(*received) != 3 {
+			t.Errorf("Expected 25 ids over 3 requests, got %d ids over %d requests", len(ids), len(*received))
+		}
+	})
+
+	t.Run("OrderedStopsAtFirstFailure", func(t *testing.T) {
+		server, received, _ := newInsertManyServer(t, 0)
+		defer server.Close()
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+
+		ids, err := collection.InsertMany(makeDocuments(30, 13), &stragollum.InsertManyOptions{ChunkSize: 10, Ordered: true})
+		var imErr *stragollum.InsertManyError
+		if !errors.As(err, &imErr) {
+			t.Fatalf("Expected an *InsertManyError, got %T: %v", err, err)
+		}
+		if len(*received) != 2 {
+			t.Errorf("Expected the insertion to stop after 2 requests, got %d", len(*received))
+		}
+		if len(ids) != 13 || len(imErr.InsertedIDs) != 13 {
+			t.Errorf("Expected 13 inserted ids, got %d", len(ids))
+		}
+		if len(imErr.Failures) != 1 || imErr.Failures[0].Index != 13 {
+			t.Fatalf("Unexpected failures: %+v", imErr.Failures)
+		}
+		var apiErr *stragollum.DataAPIResponseError
+		if !errors.As(imErr.Failures[0].Err, &apiErr) || !apiErr.HasErrorCode("DOCUMENT_ALREADY_EXISTS") {
+			t.Errorf("Unexpected failure cause: %v", imErr.Failures[0].Err)
+		}
+	})
+
+	t.Run("UnorderedPartialFailure", func(t *testing.T) {
+		server, received, _ := newInsertManyServer(t, 0)
+		defer server.Close()
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+
+		ids, err := collection.InsertMany(makeDocuments(30, 3, 25, 27), &stragollum.InsertManyOptions{ChunkSize: 10})
+		var imErr *stragollum.InsertManyError
+		if !errors.As(err, &imErr) {
+			t.Fatalf("Expected an *InsertManyError, got %T: %v", err, err)
+		}
+		if len(*received) != 3 {
+			t.Errorf("Expected all 3 chunks to be sent, got %d", len(*received))
+		}
+		if len(ids) != 27 || ids[3].String() != "doc004" {
+			t.Errorf("Unexpected inserted ids: %v", ids)
+		}
+		if len(imErr.Failures) != 3 || imErr.Failures[1].Index != 25 || imErr.Failures[2].Index != 27 {
+			t.Fatalf("Unexpected failures: %+v", imErr.Failures)
+		}
+		var apiErr *stragollum.DataAPIResponseError
+		if !errors.As(imErr.Failures[2].Err, &apiErr) || apiErr.Errors[0].Message != "duplicate doc027" {
+			t.Errorf("Unexpected failure cause: %v", imErr.Failures[2].Err)
+		}
+		if imErr.Failures[0].Document.(map[string]any)["_id"] != "doc003" {
+			t.Errorf("Unexpected failed document: %v", imErr.Failures[0].Document)
+		}
+	})
+
+	t.Run("BoundedConcurrency", func(t *testing.T) {
+		server, received, maxInFlight := newInsertManyServer(t, 20*time.Millisecond)
+		defer server.Close()
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+
+		ids, err := collection.InsertMany(makeDocuments(40), &stragollum.InsertManyOptions{ChunkSize: 5, Concurrency: 2})
+		if err != nil {
+			t.Fatalf("InsertMany failed: %v", err)
+		}
+		if len(ids) != 40 || len(*received) != 8 {
+			t.Errorf("Expected 40 ids over 8 requests, got %d ids over %d requests", len(ids), len(*received))
+		}
+		if m := atomic.LoadInt32(maxInFlight); m > 2 {
+			t.Errorf("Expected at most 2 concurrent requests, observed %d", m)
+		}
+	})
+
+	t.Run("UnorderedCanceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			cancel()
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+
+		// The chunks not launched before the cancellation are reported as failed
+		_, err := collection.InsertManyContext(ctx, makeDocuments(20), &stragollum.InsertManyOptions{ChunkSize: 5, Concurrency: 1})
+		var imErr *stragollum.InsertManyError
+		if !errors.As(err, &imErr) {
+			t.Fatalf("Expected an *InsertManyError, got %T: %v", err, err)
+		}
+		if n := atomic.LoadInt32(&requests); n != 1 {
+			t.Errorf("Expected a single request, got %d", n)
+		}
+		if len(imErr.Failures) != 20 {
+			t.Fatalf("Expected all 20 documents to fail, got %d failures", len(imErr.Failures))
+		}
+		for _, failure := range imErr.Failures[5:] {
+			if !errors.Is(failure.Err, context.Canceled) {
+				t.Errorf("Unexpected failure cause at %d: %v", failure.Index, failure.Err)
+			}
+		}
+	})
+
+	t.Run("WholeChunkFailure", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error": "unavailable"}`)
+		}))
+		defer server.Close()
+		collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+
+		ids, err := collection.InsertMany(makeDocuments(4), nil)
+		var imErr *stragollum.InsertManyError
+		if !errors.As(err, &imErr) {
+			t.Fatalf("Expected an *InsertManyError, got %T: %v", err, err)
+		}
+		if len(ids) != 0 || len(imErr.Failures) != 4 {
+			t.Errorf("Expected all 4 documents to fail, got %d ids and %d failures", len(ids), len(imErr.Failures))
+		}
+	})
+}
@@ -70,7 +70,7 @@ func TestCollection_InsertOne(t *testing.T) {
 	if err != nil {
 		t.Fatalf("InsertOne failed: %v", err)
 	}
-	if docID != expectedDocumentID {
+	if docID.String() != expectedDocumentID {
 		t.Errorf("Expected document ID %s, got %s", expectedDocumentID, docID)
 	}
 
@@ -0,0 +1,72 @@
+package stragollum_test
+
+import (
+	"errors"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+func TestCollection_FindByLexical(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if name != "find" {
+			t.Errorf("Unexpected command %q", name)
+		}
+		if command["sort"].(map[string]any)["$lexical"] != "green tea" {
+			t.Errorf("Unexpected sort: %v", command["sort"])
+		}
+		match := command["filter"].(map[string]any)["$lexical"].(map[string]any)["$match"]
+		if match != "tea" {
+			t.Errorf("Unexpected filter: %v", command["filter"])
+		}
+		if command["options"].(map[string]any)["limit"] != float64(3) {
+			t.Errorf("Unexpected options: %v", command["options"])
+		}
+	}, `{"data": {"documents": [{"_id": "t1"}, {"_id": "t2"}], "nextPageState": null}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("teas", nil)
+	var documents []map[string]any
+	err := collection.FindByLexical("green tea", stragollum.LexicalMatch("tea"), &stragollum.LexicalSearchOptions{Limit: 3}).All(&documents)
+	if err != nil {
+		t.Fatalf("FindByLexical failed: %v", err)
+	}
+	if len(documents) != 2 || documents[0]["_id"] != "t1" {
+		t.Errorf("Unexpected documents: %v", documents)
+	}
+}
+
+func TestCollection_LexicalWithVectorSort(t *testing.T) {
+	calls := 0
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		calls++
+	}, `{"data": {"documents": []}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("teas", nil)
+	sort := map[string]any{"$lexical": "tea", "$vectorize": "tea"}
+
+	cursor := collection.Find(nil, &stragollum.FindOptions{Sort: sort})
+	if cursor.Next() || !errors.Is(cursor.Err(), stragollum.ErrLexicalWithVectorSort) {
+		t.Errorf("Expected ErrLexicalWithVectorSort from Find, got %v", cursor.Err())
+	}
+	if _, err := collection.FindOne(nil, &stragollum.FindOneOptions{Sort: sort}); !errors.Is(err, stragollum.ErrLexicalWithVectorSort) {
+		t.Errorf("Expected ErrLexicalWithVectorSort from FindOne, got %v", err)
+	}
+	if _, err := collection.DeleteOne(nil, &stragollum.DeleteOneOptions{Sort: sort}); !errors.Is(err, stragollum.ErrLexicalWithVectorSort) {
+		t.Errorf("Expected ErrLexicalWithVectorSort from DeleteOne, got %v", err)
+	}
+	vectorSort := struct {
+		Lexical string                   `json:"$lexical"`
+		Vector  stragollum.DataAPIVector `dataapi:"vector"`
+	}{Lexical: "tea", Vector: stragollum.DataAPIVector{0.1}}
+	if _, _, err := collection.FindOneAndDelete(nil, &stragollum.FindOneAndDeleteOptions{Sort: vectorSort}); !errors.Is(err, stragollum.ErrLexicalWithVectorSort) {
+		t.Errorf("Expected ErrLexicalWithVectorSort from FindOneAndDelete, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("No request expected, got %d", calls)
+	}
+
+	if _, err := collection.FindOne(nil, &stragollum.FindOneOptions{Sort: stragollum.LexicalSort("tea")}); err != nil {
+		t.Errorf("FindOne with a lexical sort failed: %v", err)
+	}
+}
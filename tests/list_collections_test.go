@@ -0,0 +1,97 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"strings"
+	"testing"
+)
+
+// newFindCollectionsServer answers findCollections commands with the given collections
+// (raw JSON), recording the request paths and bodies.
+func newFindCollectionsServer(t *testing.T, rawCollections string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		body, _ := json.Marshal(payload)
+		received = append(received, r.URL.Path+" "+string(body))
+		fmt.Fprintf(w, `{"status": {"collections": %s}}`, rawCollections)
+	}))
+	return server, &received
+}
+
+const listedCollections = `[
+	{"name": "plain", "options": {}},
+	{"name": "vectors", "options": {
+		"defaultId": {"type": "uuidv7"},
+		"vector": {"dimension": 1024, "metric": "dot_product", "sourceModel": "other"},
+		"indexing": {"deny": ["blob"]}
+	}},
+	{"name": "hybrid", "options": {
+		"lexical": {"enabled": true, "analyzer": {"tokenizer": {"name": "standard", "args": {}}, "filters": [{"name": "lowercase"}]}},
+		"rerank": {"enabled": true, "service": {"provider": "nvidia", "modelName": "nvidia/llama-3.2-nv-rerankqa-1b-v2"}}
+	}}
+]`
+
+func TestDatabase_ListCollections(t *testing.T) {
+	server, received := newFindCollectionsServer(t, listedCollections)
+	defer server.Close()
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+
+	descriptors, err := db.ListCollections()
+	if err != nil {
+		t.Fatalf("ListCollections failed: %v", err)
+	}
+	if (*received)[0] != `/api/json/v1/ks1 {"findCollections":{"options":{"explain":true}}}` {
+		t.Errorf("Unexpected request: %s", (*received)[0])
+	}
+	if len(descriptors) != 3 || descriptors[0].Name != "plain" || descriptors[0].Definition.Vector != nil {
+		t.Fatalf("Unexpected descriptors: %+v", descriptors)
+	}
+
+	vectors := descriptors[1].Definition
+	if vectors.DefaultID == nil || vectors.DefaultID.Type != stragollum.DefaultIDTypeUUIDv7 {
+		t.Errorf("Unexpected defaultId: %+v", vectors.DefaultID)
+	}
+	if vectors.Vector == nil || vectors.Vector.Dimension == nil || *vectors.Vector.Dimension != 1024 || vectors.Vector.Metric != "dot_product" {
+		t.Errorf("Unexpected vector options: %+v", vectors.Vector)
+	}
+
+	// Analyzer configurations are kept as objects
+	hybrid := descriptors[2].Definition
+	if hybrid.Lexical == nil || hybrid.Lexical.Analyzer != "" || hybrid.Lexical.AnalyzerConfig["tokenizer"] == nil {
+		t.Errorf("Unexpected lexical options: %+v", hybrid.Lexical)
+	}
+	if hybrid.Rerank == nil || hybrid.Rerank.Service == nil || hybrid.Rerank.Service.Provider != "nvidia" {
+		t.Errorf("Unexpected rerank options: %+v", hybrid.Rerank)
+	}
+}
+
+func TestCollection_Options(t *testing.T) {
+	server, received := newFindCollectionsServer(t, listedCollections)
+	defer server.Close()
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+
+	definition, err := db.GetCollection("vectors", nil).Options()
+	if err != nil {
+		t.Fatalf("Options failed: %v", err)
+	}
+	if !strings.HasPrefix((*received)[0], "/api/json/v1/ks1 ") {
+		t.Errorf("Expected a keyspace-level request, got %s", (*received)[0])
+	}
+	if definition.Vector == nil || *definition.Vector.Dimension != 1024 || definition.Vector.SourceModel != "other" {
+		t.Errorf("Unexpected definition: %+v", definition)
+	}
+
+	if _, err := db.GetCollection("missing", nil).Options(); err == nil || !strings.Contains(err.Error(), `"missing"`) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"stragollum/pkg/stragollum"
+	"strings"
+	"testing"
+	"time"
+)
+
+const numbersDocument = `{
+	"_id": 9007199254740993,
+	"counter": 123456789012345678901234567890,
+	"price": 19.99,
+	"total": 12345678901234567.89,
+	"small": 42,
+	"history": [{"amount": 0.1}, 7],
+	"createdAt": {"$date": 1700000000123},
+	"$vector": [0.5, -0.25]
+}`
+
+func getNumbersCollection(url string, client *stragollum.DataAPIClient) *stragollum.Collection {
+	return client.GetDatabase(url, nil, "ks1").GetCollection("items", nil)
+}
+
+func TestNumberDecoding_Modes(t *testing.T) {
+	server, _ := newTableServer(t, map[string]string{"findOne": `{"data": {"document": ` + numbersDocument + `}}`})
+	defer server.Close()
+
+	// Default: float64, as encoding/json
+	client := stragollum.NewDataAPIClient(nil, nil)
+	if client.NumberDecoding() != stragollum.NumberDecodingFloat64 {
+		t.Errorf("Unexpected default: %s", client.NumberDecoding())
+	}
+	document, err := getNumbersCollection(server.URL, client).FindOne(nil)
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if document["_id"] != float64(9007199254740992) || document["price"] != 19.99 {
+		t.Errorf("Unexpected default numbers: %v, %v", document["_id"], document["price"])
+	}
+
+	// json.Number, inherited from the client
+	client = stragollum.NewDataAPIClient(nil, nil).WithNumberDecoding(stragollum.NumberDecodingJSONNumber)
+	collection := getNumbersCollection(server.URL, client)
+	if collection.NumberDecoding() != stragollum.NumberDecodingJSONNumber {
+		t.Errorf("Unexpected inherited mode: %s", collection.NumberDecoding())
+	}
+	document, err = collection.FindOne(nil)
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if document["_id"] != json.Number("9007199254740993") || document["total"] != json.Number("12345678901234567.89") {
+		t.Errorf("Unexpected json.Number values: %v, %v", document["_id"], document["total"])
+	}
+	if createdAt, ok := document["createdAt"].(time.Time); !ok || createdAt.UnixMilli() != 1700000000123 {
+		t.Errorf("Unexpected date: %v", document["createdAt"])
+	}
+
+	// Lossless, set on the collection
+	collection = getNumbersCollection(server.URL, stragollum.NewDataAPIClient(nil, nil)).
+		WithNumberDecoding(stragollum.NumberDecodingLossless)
+	document, err = collection.FindOne(nil)
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if document["_id"] != int64(9007199254740993) || document["small"] != int64(42) {
+		t.Errorf("Unexpected integers: %v (%T), %v (%T)", document["_id"], document["_id"], document["small"], document["small"])
+	}
+	if counter, ok := document["counter"].(*big.Int); !ok || counter.String() != "123456789012345678901234567890" {
+		t.Errorf("Unexpected big integer: %v (%T)", document["counter"], document["counter"])
+	}
+	if total, ok := document["total"].(stragollum.Decimal); !ok || total.String() != "12345678901234567.89" {
+		t.Errorf("Unexpected decimal: %v (%T)", document["total"], document["total"])
+	}
+	history := document["history"].([]interface{})
+	if amount := history[0].(map[string]interface{})["amount"]; amount.(stragollum.Decimal).String() != "0.1" || history[1] != int64(7) {
+		t.Errorf("Unexpected nested numbers: %v", history)
+	}
+	if vector := document["$vector"].([]interface{}); vector[0] != 0.5 || vector[1] != -0.25 {
+		t.Errorf("Unexpected vector: %v", vector)
+	}
+	if createdAt, ok := document["createdAt"].(time.Time); !ok || createdAt.UnixMilli() != 1700000000123 {
+		t.Errorf("Unexpected date: %v", document["createdAt"])
+	}
+}
+
+func TestNumberDecoding_RoundTrip(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{
+		"findOne":   `{"data": {"document": ` + numbersDocument + `}}`,
+		"insertOne": `{"status": {"insertedIds": [1]}}`,
+	})
+	defer server.Close()
+	collection := getNumbersCollection(server.URL, stragollum.NewDataAPIClient(nil, nil).WithNumberDecoding(stragollum.NumberDecodingLossless))
+
+	document, err := collection.FindOne(nil)
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	delete(document, "createdAt")
+	delete(document, "$vector")
+	if _, err := collection.InsertOne(document); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	expected := `{"insertOne":{"document":{"_id":9007199254740993,"counter":123456789012345678901234567890,` +
+		`"history":[{"amount":0.1},7],"price":19.99,"small":42,"total":12345678901234567.89}}}`
+	if (*received)[1] != expected {
+		t.Errorf("Unexpected request:\n%s\nexpected:\n%s", (*received)[1], expected)
+	}
+
+	// Big numbers of any kind are sent as JSON numbers
+	bigFloat, _ := new(big.Float).SetPrec(200).SetString("3.14159265358979323846264338327950288")
+	price, _ := stragollum.ParseDecimal("1e-2")
+	type Item struct {
+		Count   *big.Int
+		Ratio   *big.Float
+		Price   stragollum.Decimal
+		Raw     json.Number
+		Missing *big.Float
+	}
+	item := Item{Count: new(big.Int).Lsh(big.NewInt(1), 70), Ratio: bigFloat, Price: price, Raw: "1.50"}
+	if _, err := collection.InsertOne(item); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	expected = `{"insertOne":{"document":{"Count":1180591620717411303424,` +
+		`"Ratio":3.14159265358979323846264338327950288,"Price":0.01,"Raw":1.50,"Missing":null}}}`
+	if (*received)[2] != expected {
+		t.Errorf("Unexpected request:\n%s\nexpected:\n%s", (*received)[2], expected)
+	}
+}
+
+func TestNumberDecoding_TypedFields(t *testing.T) {
+	server, _ := newTableServer(t, map[string]string{"find": `{"data": {"documents": [` + numbersDocument + `], "nextPageState": null}}`})
+	defer server.Close()
+
+	// Typed fields are exact whatever the mode; untyped fields follow it
+	type Item struct {
+		ID      int64              `json:"_id"`
+		Counter *big.Int           `json:"counter"`
+		Total   stragollum.Decimal `json:"total"`
+		Small   interface{}        `json:"small"`
+	}
+	for _, mode := range []stragollum.NumberDecoding{stragollum.NumberDecodingFloat64, stragollum.NumberDecodingJSONNumber} {
+		collection := getNumbersCollection(server.URL, stragollum.NewDataAPIClient(nil, nil).WithNumberDecoding(mode))
+		var items []Item
+		if err := collection.Find(nil, nil).All(&items); err != nil {
+			t.Fatalf("All failed: %v", err)
+		}
+		item := items[0]
+		if item.ID != 9007199254740993 || item.Counter.String() != "123456789012345678901234567890" || item.Total.String() != "12345678901234567.89" {
+			t.Errorf("Unexpected item in mode %s: %+v", mode, item)
+		}
+		expectedSmall := interface{}(float64(42))
+		if mode == stragollum.NumberDecodingJSONNumber {
+			expectedSmall = json.Number("42")
+		}
+		if item.Small != expectedSmall {
+			t.Errorf("Unexpected untyped field in mode %s: %v (%T)", mode, item.Small, item.Small)
+		}
+	}
+}
+
+func TestNumberDecoding_Commander(t *testing.T) {
+	server, _ := newTableServer(t, map[string]string{"findOne": `{"data": {"document": {"_id": 9007199254740993}}}`})
+	defer server.Close()
+
+	commander := stragollum.NewDataAPICommander(server.URL, nil).WithNumberDecoding(stragollum.NumberDecodingLossless)
+	var response map[string]interface{}
+	if err := commander.Request(map[string]any{"findOne": map[string]any{}}, &response); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	id := response["data"].(map[string]interface{})["document"].(map[string]interface{})["_id"]
+	if id != int64(9007199254740993) {
+		t.Errorf("Unexpected ID: %v (%T)", id, id)
+	}
+}
+
+func TestNumberDecoding_Distinct(t *testing.T) {
+	server, _ := newTableServer(t, map[string]string{"find": `{"data": {"documents": [
+		{"n": 9007199254740992}, {"n": 9007199254740993}, {"n": 9007199254740993.0}, {"n": 1}, {"n": 1.0}
+	], "nextPageState": null}}`})
+	defer server.Close()
+
+	for _, mode := range []stragollum.NumberDecoding{stragollum.NumberDecodingJSONNumber, stragollum.NumberDecodingLossless} {
+		collection := getNumbersCollection(server.URL, stragollum.NewDataAPIClient(nil, nil).WithNumberDecoding(mode))
+		values, err := collection.Distinct("n", nil)
+		if err != nil {
+			t.Fatalf("Distinct failed: %v", err)
+		}
+		if len(values) != 3 {
+			t.Errorf("Unexpected distinct values in mode %s: %v", mode, values)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	cases := map[string]string{
+		"19.99":      "19.99",
+		"-0.001":     "-0.001",
+		"+1.50":      "1.50",
+		"1e3":        "1000",
+		"1.5E-3":     "0.0015",
+		".5":         "0.5",
+		"7.":         "7",
+		"0e10":       "0",
+		"-12.345e+2": "-1234.5",
+	}
+	for input, expected := range cases {
+		d, err := stragollum.ParseDecimal(input)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q) failed: %v", input, err)
+			continue
+		}
+		if d.String() != expected {
+			t.Errorf("ParseDecimal(%q) = %s, expected %s", input, d.String(), expected)
+		}
+	}
+	for _, invalid := range []string{"", "abc", "1.2.3", "1e", "NaN", "0x10", "1e99999999999"} {
+		if _, err := stragollum.ParseDecimal(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+
+	a, _ := stragollum.ParseDecimal("1.50")
+	b := stragollum.NewDecimal(big.NewInt(15), 1)
+	if a.Cmp(b) != 0 || a.Scale() != 2 || b.Float64() != 1.5 || a.Rat().RatString() != "3/2" {
+		t.Errorf("Unexpected comparison of %s and %s", a, b)
+	}
+	var zero stragollum.Decimal
+	if zero.String() != "0" || zero.Cmp(stragollum.NewDecimal(big.NewInt(0), 3)) != 0 {
+		t.Errorf("Unexpected zero Decimal: %s", zero)
+	}
+
+	var decoded struct {
+		Number stragollum.Decimal
+		Text   stragollum.Decimal
+	}
+	if err := json.Unmarshal([]byte(`{"Number": 0.30000000000000000001, "Text": "-2.5"}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	encoded, _ := json.Marshal(decoded)
+	if string(encoded) != `{"Number":0.30000000000000000001,"Text":-2.5}` {
+		t.Errorf("Unexpected round trip: %s", encoded)
+	}
+	if err := json.Unmarshal([]byte(`{"Number": true}`), &decoded); err == nil || !strings.Contains(err.Error(), "invalid decimal") {
+		t.Errorf("Expected an invalid decimal error, got %v", err)
+	}
+}
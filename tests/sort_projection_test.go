@@ -0,0 +1,126 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"strings"
+	"testing"
+)
+
+func TestSort_JSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sort     *stragollum.Sort
+		expected string
+	}{
+		{"order is kept", stragollum.NewSort().Descending("z").Ascending("a").Descending("m"), `{"z":-1,"a":1,"m":-1}`},
+		{"vector", stragollum.NewSort().Vector([]float32{0.5, -1, 2}), `{"$vector":[0.5,-1,2]}`},
+		{"vectorize", stragollum.NewSort().Vectorize("text"), `{"$vectorize":"text"}`},
+		{"lexical", stragollum.NewSort().Lexical("query"), `{"$lexical":"query"}`},
+		{"hybrid", stragollum.NewSort().Hybrid("text"), `{"$hybrid":"text"}`},
+		{"hybrid vectorize", stragollum.NewSort().HybridVectorize("v", "l"), `{"$hybrid":{"$lexical":"l","$vectorize":"v"}}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := json.Marshal(tc.sort)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if string(encoded) != tc.expected {
+				t.Errorf("Unexpected JSON:\n got %s\nwant %s", encoded, tc.expected)
+			}
+		})
+	}
+
+	for name, invalid := range map[string]*stragollum.Sort{
+		"duplicate field":    stragollum.NewSort().Ascending("a").Descending("a"),
+		"vector and field":   stragollum.NewSort().Ascending("a").Vector([]float32{1}),
+		"lexical and field":  stragollum.NewSort().Lexical("q").Ascending("a"),
+		"lexical and vector": stragollum.NewSort().Lexical("q").Vectorize("q"),
+		"empty field":        stragollum.NewSort().Ascending(""),
+	} {
+		if invalid.Err() == nil {
+			t.Errorf("Expected an invalid sort for %s", name)
+		}
+		if _, err := json.Marshal(invalid); err == nil {
+			t.Errorf("Expected a marshaling error for %s", name)
+		}
+	}
+}
+
+func TestProjection_JSON(t *testing.T) {
+	testCases := []struct {
+		name       string
+		projection *stragollum.Projection
+		expected   string
+	}{
+		{"include", stragollum.NewProjection().Include("b", "a").Exclude("_id"), `{"b":true,"a":true,"_id":false}`},
+		{"exclude", stragollum.NewProjection().Exclude("big").Slice("list", -2), `{"big":false,"list":{"$slice":-2}}`},
+		{"slice range", stragollum.NewProjection().Include("a").SliceRange("list", 1, 3), `{"a":true,"list":{"$slice":[1,3]}}`},
+		{"wildcard", stragollum.NewProjection().ExcludeAll(), `{"*":false}`},
+		{"similarity", stragollum.NewProjection().Include("a").IncludeSimilarity(), `{"a":true,"$similarity":true}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := json.Marshal(tc.projection)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if string(encoded) != tc.expected {
+				t.Errorf("Unexpected JSON:\n got %s\nwant %s", encoded, tc.expected)
+			}
+		})
+	}
+
+	for name, invalid := range map[string]*stragollum.Projection{
+		"mixed":              stragollum.NewProjection().Include("a").Exclude("b"),
+		"duplicate":          stragollum.NewProjection().Include("a", "a"),
+		"wildcard and field": stragollum.NewProjection().IncludeAll().Include("a"),
+		"empty slice":        stragollum.NewProjection().SliceRange("list", 0, 0),
+	} {
+		if invalid.Err() == nil {
+			t.Errorf("Expected an invalid projection for %s", name)
+		}
+	}
+}
+
+func TestSortProjection_Request(t *testing.T) {
+	calls := 0
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.Write([]byte(`{"data": {"documents": [], "nextPageState": null}}`))
+	}))
+	defer server.Close()
+
+	db := stragollum.NewDataAPIClient(nil, nil).WithVectorEncoding(stragollum.VectorEncodingBinary).GetDatabase(server.URL, nil, "ks1")
+	collection := db.GetCollection("coll", nil)
+
+	var documents []map[string]any
+	err := collection.Find(nil, &stragollum.FindOptions{
+		Sort:       stragollum.NewSort().Vector([]float32{1, 2}),
+		Projection: stragollum.NewProjection().Include("z", "a"),
+	}).All(&documents)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	expected := `{"find":{"filter":{},"sort":{"$vector":{"$binary":"P4AAAEAAAAA="}},"projection":{"z":true,"a":true}}}`
+	if received != expected {
+		t.Errorf("Unexpected payload:\n got %s\nwant %s", received, expected)
+	}
+
+	err = collection.Find(nil, &stragollum.FindOptions{
+		Projection: stragollum.NewProjection().Include("a").Exclude("b"),
+	}).All(&documents)
+	if err == nil || !strings.Contains(err.Error(), "cannot mix inclusion and exclusion") {
+		t.Errorf("Expected an invalid projection error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("No request expected for an invalid projection, got %d", calls-1)
+	}
+}
@@ -0,0 +1,179 @@
+package stragollum_test
+
+import (
+	"fmt"
+	"stragollum/pkg/stragollum"
+	"strings"
+	"testing"
+)
+
+const liveReadingsTables = `{"status": {"tables": [
+	{"name": "other", "definition": {"columns": {"id": "text"}, "primaryKey": "id"}},
+	{"name": "readings", "definition": {
+		"columns": {
+			"city": {"type": "text"},
+			"seq": {"type": "bigint"},
+			"notes": {"type": "text"},
+			"summary": {"type": "text"},
+			"embedding": {"type": "vector", "dimension": 1024, "service": {"provider": "nvidia", "modelName": "NV-Embed-QA"}}
+		},
+		"primaryKey": {"partitionBy": ["city"], "partitionSort": {"seq": -1}}
+	}}
+]}}`
+
+func TestTable_Alter(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{"alterTable": `{"status": {"ok": 1}}`})
+	defer server.Close()
+	table := getTestTable(server.URL)
+
+	operations := []stragollum.AlterTableOperation{
+		stragollum.AddColumns{Columns: map[string]stragollum.TableColumn{
+			"tags": {Type: stragollum.ColumnTypeSet, ValueType: stragollum.ColumnTypeText},
+		}},
+		stragollum.DropColumns{Columns: []string{"notes", "summary"}},
+		stragollum.AddVectorize{Columns: map[string]stragollum.VectorServiceOptions{
+			"embedding": {Provider: "openai", ModelName: "text-embedding-3-small"},
+		}},
+		stragollum.DropVectorize{Columns: []string{"embedding"}},
+	}
+	for _, operation := range operations {
+		if err := table.Alter(operation); err != nil {
+			t.Fatalf("Alter failed: %v", err)
+		}
+	}
+
+	expected := []string{
+		`{"alterTable":{"operation":{"add":{"columns":{"tags":{"type":"set","valueType":"text"}}}}}}`,
+		`{"alterTable":{"operation":{"drop":{"columns":["notes","summary"]}}}}`,
+		`{"alterTable":{"operation":{"addVectorize":{"columns":{"embedding":{"modelName":"text-embedding-3-small","provider":"openai"}}}}}}`,
+		`{"alterTable":{"operation":{"dropVectorize":{"columns":["embedding"]}}}}`,
+	}
+	if strings.Join(*received, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected requests:\n%s", strings.Join(*received, "\n"))
+	}
+
+	// Invalid operations are not sent
+	if err := table.Alter(stragollum.DropColumns{}); err == nil {
+		t.Error("Expected an error for an empty operation")
+	}
+	if err := table.Alter(stragollum.AddColumns{Columns: map[string]stragollum.TableColumn{"m": {Type: stragollum.ColumnTypeMap}}}); err == nil {
+		t.Error("Expected an error for an invalid column")
+	}
+	if len(*received) != len(expected) {
+		t.Errorf("Unexpected requests sent: %d", len(*received))
+	}
+}
+
+func TestTable_Definition(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{"listTables": liveReadingsTables})
+	defer server.Close()
+
+	definition, err := getTestTable(server.URL).Definition()
+	if err != nil {
+		t.Fatalf("Definition failed: %v", err)
+	}
+	if (*received)[0] != `{"listTables":{"options":{"explain":true}}}` {
+		t.Errorf("Unexpected request: %s", (*received)[0])
+	}
+	if len(definition.Columns) != 5 || definition.Columns["embedding"].Service.Provider != "nvidia" {
+		t.Errorf("Unexpected columns: %+v", definition.Columns)
+	}
+	if sort := definition.PrimaryKey.PartitionSort; len(sort) != 1 || sort[0].Column != "seq" || sort[0].Order != stragollum.SortDescending {
+		t.Errorf("Unexpected primary key: %+v", definition.PrimaryKey)
+	}
+
+	_, err = stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetTable("missing", nil).Definition()
+	if err == nil || !strings.Contains(err.Error(), `table "missing" not found`) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestTable_PlanAlter(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{"listTables": liveReadingsTables})
+	defer server.Close()
+	table := getTestTable(server.URL)
+
+	desired := func() *stragollum.TableDefinition {
+		return stragollum.NewTableDefinition().
+			AddColumn("city", stragollum.ColumnTypeText).
+			AddColumn("seq", stragollum.ColumnTypeBigInt).
+			AddColumn("notes", stragollum.ColumnTypeText).
+			AddColumn("summary", stragollum.ColumnTypeText).
+			AddVectorColumn("embedding", 1024, &stragollum.VectorServiceOptions{Provider: "nvidia", ModelName: "NV-Embed-QA"}).
+			WithPartitionBy("city").
+			WithPartitionSort("seq", stragollum.SortDescending)
+	}
+
+	// Up to date
+	operations, err := table.PlanAlter(desired())
+	if err != nil || len(operations) != 0 {
+		t.Errorf("Expected no operations, got %+v, %v", operations, err)
+	}
+
+	// Columns added and dropped, vectorize service changed
+	definition := desired().
+		AddSetColumn("tags", stragollum.ColumnTypeText).
+		AddVectorColumn("embedding", 0, &stragollum.VectorServiceOptions{Provider: "openai", ModelName: "text-embedding-3-small"})
+	delete(definition.Columns, "notes")
+	delete(definition.Columns, "summary")
+	operations, err = table.PlanAlter(definition)
+	if err != nil {
+		t.Fatalf("PlanAlter failed: %v", err)
+	}
+	expected := []stragollum.AlterTableOperation{
+		stragollum.DropVectorize{Columns: []string{"embedding"}},
+		stragollum.DropColumns{Columns: []string{"notes", "summary"}},
+		stragollum.AddColumns{Columns: map[string]stragollum.TableColumn{
+			"tags": {Type: stragollum.ColumnTypeSet, ValueType: stragollum.ColumnTypeText},
+		}},
+		stragollum.AddVectorize{Columns: map[string]stragollum.VectorServiceOptions{
+			"embedding": {Provider: "openai", ModelName: "text-embedding-3-small"},
+		}},
+	}
+	if fmt.Sprintf("%+v", operations) != fmt.Sprintf("%+v", expected) {
+		t.Errorf("Unexpected operations:\n%+v\nexpected:\n%+v", operations, expected)
+	}
+
+	// The whole vectorize service is compared, not only the provider and model
+	definition = desired().AddVectorColumn("embedding", 1024, &stragollum.VectorServiceOptions{
+		Provider: "nvidia", ModelName: "NV-Embed-QA", Authentication: map[string]any{"providerKey": "shared-key"},
+	})
+	operations, err = table.PlanAlter(definition)
+	if err != nil {
+		t.Fatalf("PlanAlter failed: %v", err)
+	}
+	expected = []stragollum.AlterTableOperation{
+		stragollum.DropVectorize{Columns: []string{"embedding"}},
+		stragollum.AddVectorize{Columns: map[string]stragollum.VectorServiceOptions{
+			"embedding": {Provider: "nvidia", ModelName: "NV-Embed-QA", Authentication: map[string]any{"providerKey": "shared-key"}},
+		}},
+	}
+	if fmt.Sprintf("%+v", operations) != fmt.Sprintf("%+v", expected) {
+		t.Errorf("Unexpected operations:\n%+v\nexpected:\n%+v", operations, expected)
+	}
+
+	// Changes that cannot be applied
+	_, err = table.PlanAlter(desired().AddColumn("notes", stragollum.ColumnTypeInt))
+	if err == nil || !strings.Contains(err.Error(), `type of column "notes" cannot be changed`) {
+		t.Errorf("Expected a column type error, got %v", err)
+	}
+	_, err = table.PlanAlter(desired().WithPartitionBy("city", "notes"))
+	if err == nil || !strings.Contains(err.Error(), "primary key cannot be changed") {
+		t.Errorf("Expected a primary key error, got %v", err)
+	}
+
+	// Nothing to compare with
+	if _, err := desired().AlterOperations(nil); err == nil || !strings.Contains(err.Error(), "must be created") {
+		t.Errorf("Expected an error for a missing current definition, got %v", err)
+	}
+	if _, err := table.PlanAlter(nil); err == nil || !strings.Contains(err.Error(), "missing table definition") {
+		t.Errorf("Expected an error for a missing desired definition, got %v", err)
+	}
+
+	// A dry run only reads the definition
+	for _, request := range *received {
+		if !strings.Contains(request, "listTables") {
+			t.Errorf("Unexpected request: %s", request)
+		}
+	}
+}
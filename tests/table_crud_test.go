@@ -0,0 +1,360 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTableServer answers each command with the response found under its name in responses
+// (raw JSON), recording the request bodies.
+func newTableServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read request: %v", err)
+		}
+		received = append(received, string(body))
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+		for command := range payload {
+			fmt.Fprint(w, responses[command])
+		}
+	}))
+	return server, &received
+}
+
+const readingsSchema = `{
+	"city": {"type": "text"},
+	"seq": {"type": "bigint"},
+	"population": {"type": "varint"},
+	"price": {"type": "decimal"},
+	"ratio": {"type": "float"},
+	"score": {"type": "double"},
+	"payload": {"type": "blob"},
+	"at": {"type": "timestamp"},
+	"day": {"type": "date"},
+	"ref": {"type": "uuid"},
+	"tags": {"type": "set", "valueType": "text"},
+	"counts": {"type": "map", "keyType": "text", "valueType": "bigint"},
+	"byDay": {"type": "map", "keyType": "int", "valueType": "text"},
+	"embedding": {"type": "vector", "dimension": 2}
+}`
+
+const readingsRow = `{
+	"city": "Rome",
+	"seq": 9007199254740993,
+	"population": 123456789012345678901234567890,
+	"price": 123.4500,
+	"ratio": 0.5,
+	"score": "NaN",
+	"payload": {"$binary": "AQID"},
+	"at": "2024-05-01T10:20:30.123Z",
+	"day": "2024-05-01",
+	"ref": "0190a6e4-7b1c-7c3e-9f00-5a0e6f1b2c3d",
+	"tags": ["a", "b"],
+	"counts": {"x": 9007199254740993},
+	"byDay": [[1, "mon"], [2, "tue"]],
+	"embedding": {"$binary": "PwAAAD8AAAA="}
+}`
+
+func getTestTable(url string) *stragollum.Table {
+	return stragollum.NewDataAPIClient(nil, nil).GetDatabase(url, nil, "ks1").GetTable("readings", nil)
+}
+
+func TestTable_InsertOne(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{
+		"insertOne": `{"status": {
+			"primaryKeySchema": {"city": {"type": "text"}, "seq": {"type": "bigint"}, "at": {"type": "timestamp"}},
+			"insertedIds": [["Rome", 9007199254740993, "2024-05-01T10:20:30.123Z"]]
+		}}`,
+	})
+	defer server.Close()
+	table := getTestTable(server.URL)
+
+	ref, _ := stragollum.ParseUUID("0190a6e4-7b1c-7c3e-9f00-5a0e6f1b2c3d")
+	at := time.Date(2024, 5, 1, 10, 20, 30, 123000000, time.UTC)
+	key, err := table.InsertOne(map[string]any{
+		"city": "Rome", "seq": int64(9007199254740993), "at": at, "ref": ref, "payload": []byte{1, 2, 3},
+	})
+	if err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+
+	// Dates, UUIDs and blobs are serialized as tables expect them
+	expected := `{"insertOne":{"document":{"at":"2024-05-01T10:20:30.123Z","city":"Rome",` +
+		`"payload":{"$binary":"AQID"},"ref":"0190a6e4-7b1c-7c3e-9f00-5a0e6f1b2c3d","seq":9007199254740993}}}`
+	if (*received)[0] != expected {
+		t.Errorf("Unexpected request:\n%s\nexpected:\n%s", (*received)[0], expected)
+	}
+
+	if strings.Join(key.Columns, ",") != "city,seq,at" {
+		t.Errorf("Unexpected key columns: %v", key.Columns)
+	}
+	if seq, _ := key.Value("seq"); seq != int64(9007199254740993) {
+		t.Errorf("Unexpected seq: %v (%T)", seq, seq)
+	}
+	if keyAt, _ := key.Value("at"); !keyAt.(time.Time).Equal(at) {
+		t.Errorf("Unexpected at: %v", keyAt)
+	}
+	if filter := key.Filter(); len(filter) != 3 || filter["city"] != "Rome" {
+		t.Errorf("Unexpected filter: %v", filter)
+	}
+}
+
+func TestTable_InsertMany(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{
+		"insertMany": `{"status": {
+			"primaryKeySchema": {"city": {"type": "text"}, "seq": {"type": "bigint"}},
+			"documentResponses": [
+				{"_id": ["Rome", 1], "status": "OK"},
+				{"_id": ["Rome", 2], "status": "ERROR", "errorsIdx": 0},
+				{"_id": ["Oslo", 3], "status": "OK"}
+			]
+		}, "errors": [{"errorCode": "INVALID_COLUMN_VALUES", "message": "bad row"}]}`,
+	})
+	defer server.Close()
+	table := getTestTable(server.URL)
+
+	rows := []interface{}{
+		map[string]any{"city": "Rome", "seq": 1},
+		map[string]any{"city": "Rome", "seq": 2, "score": "bad"},
+		map[string]any{"city": "Oslo", "seq": 3},
+	}
+	keys, err := table.InsertMany(rows, nil)
+	var imErr *stragollum.TableInsertManyError
+	if !errors.As(err, &imErr) {
+		t.Fatalf("Expected a *TableInsertManyError, got %T: %v", err, err)
+	}
+	if len(keys) != 2 || keys[1].Values[0] != "Oslo" || keys[1].Values[1] != int64(3) {
+		t.Errorf("Unexpected keys: %+v", keys)
+	}
+	if len(imErr.Failures) != 1 || imErr.Failures[0].Index != 1 || !strings.Contains(imErr.Failures[0].Err.Error(), "bad row") {
+		t.Errorf("Unexpected failures: %+v", imErr.Failures)
+	}
+	if !strings.Contains((*received)[0], `"returnDocumentResponses":true`) {
+		t.Errorf("Unexpected request: %s", (*received)[0])
+	}
+}
+
+func TestTable_InsertMany_UndecodableKey(t *testing.T) {
+	server, _ := newTableServer(t, map[string]string{
+		"insertMany": `{"status": {
+			"primaryKeySchema": {"city": {"type": "text"}, "seq": {"type": "bigint"}},
+			"documentResponses": [
+				{"_id": ["Rome", 1], "status": "OK"},
+				{"_id": ["Rome", "not a number"], "status": "OK"}
+			]
+		}}`,
+	})
+	defer server.Close()
+
+	rows := []interface{}{map[string]any{"city": "Rome", "seq": 1}, map[string]any{"city": "Rome", "seq": 2}}
+	keys, err := getTestTable(server.URL).InsertMany(rows, nil)
+
+	// The key of the second row cannot be decoded: it is reported as an error, not a zero key
+	var imErr *stragollum.TableInsertManyError
+	if !errors.As(err, &imErr) {
+		t.Fatalf("Expected a *TableInsertManyError, got %T: %v", err, err)
+	}
+	if len(imErr.Failures) != 1 || imErr.Failures[0].Index != 1 || !strings.Contains(imErr.Failures[0].Err.Error(), "failed to decode the inserted primary key") {
+		t.Errorf("Unexpected failures: %+v", imErr.Failures)
+	}
+	if len(keys) != 1 || keys[0].Values[0] != "Rome" {
+		t.Errorf("Unexpected keys: %+v", keys)
+	}
+}
+
+func TestTable_FindOne(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{
+		"findOne": `{"data": {"document": ` + readingsRow + `}, "status": {"projectionSchema": ` + readingsSchema + `}}`,
+	})
+	defer server.Close()
+	table := getTestTable(server.URL)
+
+	row, err := table.FindOne(map[string]any{"city": "Rome"}, &stragollum.FindOneOptions{Projection: map[string]any{"*": 1}})
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if (*received)[0] != `{"findOne":{"filter":{"city":"Rome"},"projection":{"*":1}}}` {
+		t.Errorf("Unexpected request: %s", (*received)[0])
+	}
+
+	// Values are decoded according to the column types, without loss
+	if row["seq"] != int64(9007199254740993) {
+		t.Errorf("Unexpected bigint: %v (%T)", row["seq"], row["seq"])
+	}
+	population, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	if v, ok := row["population"].(*big.Int); !ok || v.Cmp(population) != 0 {
+		t.Errorf("Unexpected varint: %v (%T)", row["population"], row["population"])
+	}
+	if row["price"] != json.Number("123.4500") {
+		t.Errorf("Unexpected decimal: %v (%T)", row["price"], row["price"])
+	}
+	if row["ratio"] != float32(0.5) {
+		t.Errorf("Unexpected float: %v (%T)", row["ratio"], row["ratio"])
+	}
+	if score, ok := row["score"].(float64); !ok || !math.IsNaN(score) {
+		t.Errorf("Unexpected double: %v", row["score"])
+	}
+	if payload, ok := row["payload"].([]byte); !ok || string(payload) != "\x01\x02\x03" {
+		t.Errorf("Unexpected blob: %v", row["payload"])
+	}
+	if at, ok := row["at"].(time.Time); !ok || at.UnixMilli() != time.Date(2024, 5, 1, 10, 20, 30, 123000000, time.UTC).UnixMilli() {
+		t.Errorf("Unexpected timestamp: %v", row["at"])
+	}
+	if row["day"] != "2024-05-01" {
+		t.Errorf("Unexpected date: %v", row["day"])
+	}
+	if ref, ok := row["ref"].(stragollum.UUID); !ok || ref.String() != "0190a6e4-7b1c-7c3e-9f00-5a0e6f1b2c3d" {
+		t.Errorf("Unexpected uuid: %v", row["ref"])
+	}
+	if tags, ok := row["tags"].([]interface{}); !ok || len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("Unexpected set: %v", row["tags"])
+	}
+	if counts, ok := row["counts"].(map[string]interface{}); !ok || counts["x"] != int64(9007199254740993) {
+		t.Errorf("Unexpected map: %v", row["counts"])
+	}
+	if byDay, ok := row["byDay"].(map[interface{}]interface{}); !ok || byDay[2] != "tue" {
+		t.Errorf("Unexpected map with int keys: %v", row["byDay"])
+	}
+	if embedding, ok := row["embedding"].(stragollum.DataAPIVector); !ok || len(embedding) != 2 || embedding[0] != 0.5 {
+		t.Errorf("Unexpected vector: %v", row["embedding"])
+	}
+
+	// No row found
+	emptyServer, _ := newTableServer(t, map[string]string{"findOne": `{"data": {"document": null}, "status": {"projectionSchema": {}}}`})
+	defer emptyServer.Close()
+	row, err = getTestTable(emptyServer.URL).FindOne(nil)
+	if err != nil || row != nil {
+		t.Errorf("Expected no row, got %v, %v", row, err)
+	}
+}
+
+func TestTable_Find(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{
+		"find": `{"data": {"documents": [` + readingsRow + `], "nextPageState": null}, "status": {"projectionSchema": ` + readingsSchema + `}}`,
+	})
+	defer server.Close()
+	table := getTestTable(server.URL)
+
+	type reading struct {
+		City       string               `json:"city"`
+		Seq        int64                `json:"seq"`
+		Population *big.Int             `json:"population"`
+		Price      json.Number          `json:"price"`
+		Payload    []byte               `json:"payload"`
+		At         time.Time            `json:"at"`
+		Ref        stragollum.UUID      `json:"ref"`
+		Counts     map[string]int64     `json:"counts"`
+		Embedding  []float32            `json:"embedding"`
+		Tags       []string             `json:"tags"`
+		Unused     *stragollum.ObjectID `json:"unused,omitempty"`
+	}
+	cursor := table.Find(map[string]any{"city": "Rome"}, &stragollum.FindOptions{Limit: 5})
+	var readings []reading
+	if err := cursor.All(&readings); err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if (*received)[0] != `{"find":{"filter":{"city":"Rome"},"options":{"limit":5}}}` {
+		t.Errorf("Unexpected request: %s", (*received)[0])
+	}
+	if len(readings) != 1 {
+		t.Fatalf("Unexpected readings: %+v", readings)
+	}
+	r := readings[0]
+	if r.Seq != 9007199254740993 || r.Population.String() != "123456789012345678901234567890" || r.Price != "123.4500" {
+		t.Errorf("Unexpected numbers: %+v", r)
+	}
+	if string(r.Payload) != "\x01\x02\x03" || len(r.Embedding) != 2 || r.Embedding[1] != 0.5 || r.Counts["x"] != 9007199254740993 {
+		t.Errorf("Unexpected values: %+v", r)
+	}
+	if r.At.IsZero() || r.Ref.Version() != 7 || len(r.Tags) != 2 {
+		t.Errorf("Unexpected values: %+v", r)
+	}
+}
+
+func TestTable_Find_UntypedStructFields(t *testing.T) {
+	server, _ := newTableServer(t, map[string]string{
+		"find": `{"data": {"documents": [` + readingsRow + `], "nextPageState": null}, "status": {"projectionSchema": ` + readingsSchema + `}}`,
+	})
+	defer server.Close()
+
+	// Numbers landing in interface{} fields are exact, whatever their column type
+	type reading struct {
+		Seq        any                    `json:"seq"`
+		Population interface{}            `json:"population"`
+		Price      any                    `json:"price"`
+		Counts     map[string]interface{} `json:"counts"`
+	}
+	var readings []reading
+	if err := getTestTable(server.URL).Find(nil, nil).All(&readings); err != nil || len(readings) != 1 {
+		t.Fatalf("All failed: %v, %+v", err, readings)
+	}
+	r := readings[0]
+	if r.Seq != json.Number("9007199254740993") || r.Counts["x"] != json.Number("9007199254740993") {
+		t.Errorf("Unexpected bigint values: %v (%T), %v", r.Seq, r.Seq, r.Counts)
+	}
+	if r.Population != json.Number("123456789012345678901234567890") || r.Price != json.Number("123.4500") {
+		t.Errorf("Unexpected values: %+v", r)
+	}
+}
+
+func TestTable_UpdateAndDelete(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{
+		"updateOne":  `{"status": {"matchedCount": 1, "modifiedCount": 1}}`,
+		"deleteOne":  `{"status": {"deletedCount": -1}}`,
+		"deleteMany": `{"status": {"deletedCount": -1}}`,
+	})
+	defer server.Close()
+	table := getTestTable(server.URL)
+	key := map[string]any{"city": "Rome", "seq": 1}
+
+	if err := table.UpdateOne(key, stragollum.NewUpdate().Set("score", 1.5).Unset("tags")); err != nil {
+		t.Fatalf("UpdateOne failed: %v", err)
+	}
+	if err := table.UpdateOne(key, stragollum.NewUpdate().Inc("score", 1)); !errors.Is(err, stragollum.ErrTableUpdateOperator) {
+		t.Errorf("Expected ErrTableUpdateOperator, got %v", err)
+	}
+	if err := table.DeleteOne(key); err != nil {
+		t.Fatalf("DeleteOne failed: %v", err)
+	}
+	if err := table.DeleteMany(map[string]any{"city": "Rome"}); err != nil {
+		t.Fatalf("DeleteMany failed: %v", err)
+	}
+	type optionalFilter struct {
+		City string `json:"city,omitempty"`
+	}
+	for _, emptyFilter := range []interface{}{nil, map[string]any{}, struct{}{}, optionalFilter{}, json.RawMessage("{}")} {
+		if err := table.DeleteMany(emptyFilter); !errors.Is(err, stragollum.ErrEmptyFilter) {
+			t.Errorf("Expected ErrEmptyFilter for %v, got %v", emptyFilter, err)
+		}
+	}
+	if err := table.DeleteAll(); err != nil {
+		t.Fatalf("DeleteAll failed: %v", err)
+	}
+
+	expected := []string{
+		`{"updateOne":{"filter":{"city":"Rome","seq":1},"update":{"$set":{"score":1.5},"$unset":{"tags":""}}}}`,
+		`{"deleteOne":{"filter":{"city":"Rome","seq":1}}}`,
+		`{"deleteMany":{"filter":{"city":"Rome"}}}`,
+		`{"deleteMany":{"filter":{}}}`,
+	}
+	if strings.Join(*received, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected requests:\n%s", strings.Join(*received, "\n"))
+	}
+}
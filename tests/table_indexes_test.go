@@ -0,0 +1,122 @@
+package stragollum_test
+
+import (
+	"stragollum/pkg/stragollum"
+	"strings"
+	"testing"
+)
+
+func TestTable_CreateIndexes(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{
+		"createIndex":       `{"status": {"ok": 1}}`,
+		"createVectorIndex": `{"status": {"ok": 1}}`,
+		"createTextIndex":   `{"status": {"ok": 1}}`,
+		"dropIndex":         `{"status": {"ok": 1}}`,
+	})
+	defer server.Close()
+	table := getTestTable(server.URL)
+	no := false
+
+	calls := []func() error{
+		func() error { return table.CreateIndex("city_idx", "city") },
+		func() error {
+			return table.CreateIndex("notes_idx", "notes", &stragollum.CreateIndexOptions{
+				Ascii: &no, CaseSensitive: &no, IfNotExists: true,
+			})
+		},
+		func() error {
+			return table.CreateIndex("counts_keys", "counts", &stragollum.CreateIndexOptions{MapTarget: stragollum.IndexMapKeys})
+		},
+		func() error {
+			return table.CreateIndex("counts_values", "counts", &stragollum.CreateIndexOptions{MapTarget: stragollum.IndexMapValues})
+		},
+		func() error { return table.CreateIndex("counts_entries", "counts", nil) },
+		func() error {
+			return table.CreateVectorIndex("embedding_idx", "embedding", "dot_product", "openai-v3-small")
+		},
+		func() error {
+			return table.CreateVectorIndex("embedding_idx", "embedding", "", "", &stragollum.CreateVectorIndexOptions{IfNotExists: true})
+		},
+		func() error { return table.CreateTextIndex("body_idx", "body", "english") },
+		func() error {
+			analyzer := map[string]any{"tokenizer": map[string]any{"name": "standard"}, "filters": []string{"lowercase"}}
+			return table.CreateTextIndex("body_idx", "body", analyzer, &stragollum.CreateTextIndexOptions{IfNotExists: true})
+		},
+		func() error {
+			return stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").DropIndex("city_idx", &stragollum.DropIndexOptions{IfExists: true})
+		},
+	}
+	for i, call := range calls {
+		if err := call(); err != nil {
+			t.Fatalf("Call %d failed: %v", i, err)
+		}
+	}
+
+	expected := []string{
+		`{"createIndex":{"name":"city_idx","definition":{"column":"city"}}}`,
+		`{"createIndex":{"name":"notes_idx","definition":{"column":"notes","options":{"ascii":false,"caseSensitive":false}},"options":{"ifNotExists":true}}}`,
+		`{"createIndex":{"name":"counts_keys","definition":{"column":{"counts":"$keys"}}}}`,
+		`{"createIndex":{"name":"counts_values","definition":{"column":{"counts":"$values"}}}}`,
+		`{"createIndex":{"name":"counts_entries","definition":{"column":"counts"}}}`,
+		`{"createVectorIndex":{"name":"embedding_idx","definition":{"column":"embedding","options":{"metric":"dot_product","sourceModel":"openai-v3-small"}}}}`,
+		`{"createVectorIndex":{"name":"embedding_idx","definition":{"column":"embedding"},"options":{"ifNotExists":true}}}`,
+		`{"createTextIndex":{"name":"body_idx","definition":{"column":"body","options":{"analyzer":"english"}}}}`,
+		`{"createTextIndex":{"name":"body_idx","definition":{"column":"body","options":{"analyzer":{"filters":["lowercase"],"tokenizer":{"name":"standard"}}}},"options":{"ifNotExists":true}}}`,
+		`{"dropIndex":{"name":"city_idx","options":{"ifExists":true}}}`,
+	}
+	if strings.Join(*received, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected requests:\n%s\nexpected:\n%s", strings.Join(*received, "\n"), strings.Join(expected, "\n"))
+	}
+
+	if err := table.CreateIndex("bad", "counts", &stragollum.CreateIndexOptions{MapTarget: "$all"}); err == nil {
+		t.Error("Expected an error for an invalid map target")
+	}
+}
+
+func TestTable_ListIndexes(t *testing.T) {
+	server, received := newTableServer(t, map[string]string{
+		"listIndexes": `{"status": {"indexes": [
+			{"name": "city_idx", "indexType": "regular", "definition": {"column": "city", "options": {"ascii": false, "caseSensitive": true, "normalize": false}}},
+			{"name": "counts_keys", "indexType": "regular", "definition": {"column": {"counts": "$keys"}, "options": {}}},
+			{"name": "embedding_idx", "indexType": "vector", "definition": {"column": "embedding", "options": {"metric": "cosine", "sourceModel": "other"}}},
+			{"name": "legacy_idx", "indexType": "UNKNOWN", "definition": {"column": "UNKNOWN", "apiSupport": {"createIndex": false, "filter": false}}}
+		]}}`,
+	})
+	defer server.Close()
+	table := getTestTable(server.URL)
+
+	indexes, err := table.ListIndexes()
+	if err != nil {
+		t.Fatalf("ListIndexes failed: %v", err)
+	}
+	if (*received)[0] != `{"listIndexes":{"options":{"explain":true}}}` {
+		t.Errorf("Unexpected request: %s", (*received)[0])
+	}
+	if len(indexes) != 4 {
+		t.Fatalf("Unexpected indexes: %+v", indexes)
+	}
+	if d := indexes[0].Definition; d.Column != "city" || d.MapTarget != stragollum.IndexMapEntries || d.Options["caseSensitive"] != true {
+		t.Errorf("Unexpected definition: %+v", d)
+	}
+	if d := indexes[1].Definition; d.Column != "counts" || d.MapTarget != stragollum.IndexMapKeys {
+		t.Errorf("Unexpected definition: %+v", d)
+	}
+	if indexes[2].IndexType != stragollum.IndexTypeVector || indexes[2].Definition.Options["metric"] != "cosine" {
+		t.Errorf("Unexpected index: %+v", indexes[2])
+	}
+	if indexes[3].Definition.APISupport["filter"] != false {
+		t.Errorf("Unexpected index: %+v", indexes[3])
+	}
+
+	namesServer, received := newTableServer(t, map[string]string{
+		"listIndexes": `{"status": {"indexes": ["city_idx", "embedding_idx"]}}`,
+	})
+	defer namesServer.Close()
+	names, err := getTestTable(namesServer.URL).ListIndexNames()
+	if err != nil {
+		t.Fatalf("ListIndexNames failed: %v", err)
+	}
+	if (*received)[0] != `{"listIndexes":{}}` || strings.Join(names, ",") != "city_idx,embedding_idx" {
+		t.Errorf("Unexpected names %v from request %s", names, (*received)[0])
+	}
+}
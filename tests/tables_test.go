@@ -0,0 +1,197 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"strings"
+	"testing"
+)
+
+// newTableDDLServer answers every command with the given (raw JSON) response, recording
+// the request bodies.
+func newTableDDLServer(t *testing.T, rawResponse string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read request: %v", err)
+		}
+		received = append(received, string(body))
+		fmt.Fprint(w, rawResponse)
+	}))
+	return server, &received
+}
+
+func TestDatabase_CreateTable(t *testing.T) {
+	server, received := newTableDDLServer(t, `{"status": {"ok": 1}}`)
+	defer server.Close()
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+
+	definition := stragollum.NewTableDefinition().
+		AddColumn("city", stragollum.ColumnTypeText).
+		AddColumn("day", stragollum.ColumnTypeDate).
+		AddColumn("seq", stragollum.ColumnTypeBigInt).
+		AddSetColumn("tags", stragollum.ColumnTypeText).
+		AddMapColumn("extra", stragollum.ColumnTypeText, stragollum.ColumnTypeInt).
+		AddVectorColumn("embedding", 3, nil).
+		WithPartitionBy("city").
+		WithPartitionSort("day", stragollum.SortDescending).
+		WithPartitionSort("seq", stragollum.SortAscending)
+
+	table, err := db.CreateTable("readings", definition, &stragollum.CreateTableOptions{IfNotExists: true})
+	if err != nil {
+		t.Fatalf("CreateTable failed: %v", err)
+	}
+	if table.Name() != "readings" || table.Keyspace() != "ks1" {
+		t.Errorf("Unexpected table: %s.%s", table.Keyspace(), table.Name())
+	}
+
+	expected := `{"createTable":{"name":"readings","definition":{"columns":{` +
+		`"city":{"type":"text"},"day":{"type":"date"},"embedding":{"type":"vector","dimension":3},` +
+		`"extra":{"type":"map","keyType":"text","valueType":"int"},"seq":{"type":"bigint"},` +
+		`"tags":{"type":"set","valueType":"text"}},` +
+		`"primaryKey":{"partitionBy":["city"],"partitionSort":{"day":-1,"seq":1}}},` +
+		`"options":{"ifNotExists":true}}}`
+	if (*received)[0] != expected {
+		t.Errorf("Unexpected request:\n%s\nexpected:\n%s", (*received)[0], expected)
+	}
+}
+
+func TestTableDefinition_Validate(t *testing.T) {
+	cases := []struct {
+		name       string
+		definition *stragollum.TableDefinition
+		message    string
+	}{
+		{"no columns", stragollum.NewTableDefinition().WithPartitionBy("a"), "no columns"},
+		{"no partition", stragollum.NewTableDefinition().AddColumn("a", stragollum.ColumnTypeText), "no partition columns"},
+		{"undefined key column", stragollum.NewTableDefinition().AddColumn("a", stragollum.ColumnTypeText).WithPartitionBy("b"), `"b" is not defined`},
+		{"repeated key column", stragollum.NewTableDefinition().AddColumn("a", stragollum.ColumnTypeText).
+			WithPartitionBy("a").WithPartitionSort("a", stragollum.SortAscending), "more than once"},
+		{"bad order", stragollum.NewTableDefinition().AddColumn("a", stragollum.ColumnTypeText).AddColumn("b", stragollum.ColumnTypeInt).
+			WithPartitionBy("a").WithPartitionSort("b", 2), "invalid order"},
+		{"list without value type", stragollum.NewTableDefinition().AddColumn("a", stragollum.ColumnTypeText).
+			AddListColumn("l", "").WithPartitionBy("a"), "value type"},
+		{"vector without dimension", stragollum.NewTableDefinition().AddColumn("a", stragollum.ColumnTypeText).
+			AddVectorColumn("v", 0, nil).WithPartitionBy("a"), "dimension or a service"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.definition.Validate()
+			if err == nil || !strings.Contains(err.Error(), tc.message) {
+				t.Errorf("Expected an error containing %q, got %v", tc.message, err)
+			}
+		})
+	}
+
+	// Invalid definitions are not sent
+	server, received := newTableDDLServer(t, `{"status": {"ok": 1}}`)
+	defer server.Close()
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+	if _, err := db.CreateTable("t", cases[0].definition); err == nil {
+		t.Error("Expected an error for an invalid definition")
+	}
+	if len(*received) != 0 {
+		t.Errorf("Expected no request, got %v", *received)
+	}
+
+	vectorize := stragollum.NewTableDefinition().AddColumn("a", stragollum.ColumnTypeText).
+		AddVectorColumn("v", 0, &stragollum.VectorServiceOptions{Provider: "nvidia", ModelName: "NV-Embed-QA"}).WithPartitionBy("a")
+	if err := vectorize.Validate(); err != nil {
+		t.Errorf("Unexpected error for a vectorize column: %v", err)
+	}
+}
+
+func TestDatabase_DropTable(t *testing.T) {
+	server, received := newTableDDLServer(t, `{"status": {"ok": 1}}`)
+	defer server.Close()
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+
+	if err := db.DropTable("readings"); err != nil {
+		t.Fatalf("DropTable failed: %v", err)
+	}
+	if err := db.DropTable("readings", &stragollum.DropTableOptions{IfExists: true}); err != nil {
+		t.Fatalf("DropTable failed: %v", err)
+	}
+	if (*received)[0] != `{"dropTable":{"name":"readings"}}` || (*received)[1] != `{"dropTable":{"name":"readings","options":{"ifExists":true}}}` {
+		t.Errorf("Unexpected requests: %v", *received)
+	}
+
+	nokServer, _ := newTableDDLServer(t, `{"status": {"ok": 0}}`)
+	defer nokServer.Close()
+	if err := stragollum.NewDataAPIClient(nil, nil).GetDatabase(nokServer.URL, nil, "ks1").DropTable("readings"); err == nil {
+		t.Error("Expected error when status.ok != 1, got nil")
+	}
+}
+
+func TestDatabase_ListTableNames(t *testing.T) {
+	server, received := newTableDDLServer(t, `{"status": {"tables": ["a", "b"]}}`)
+	defer server.Close()
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+
+	names, err := db.ListTableNames()
+	if err != nil {
+		t.Fatalf("ListTableNames failed: %v", err)
+	}
+	if strings.Join(names, ",") != "a,b" || (*received)[0] != `{"listTables":{}}` {
+		t.Errorf("Unexpected names %v for request %s", names, (*received)[0])
+	}
+}
+
+func TestDatabase_ListTables(t *testing.T) {
+	server, received := newTableDDLServer(t, `{"status": {"tables": [
+		{"name": "readings", "definition": {
+			"columns": {
+				"city": {"type": "text"},
+				"seq": {"type": "bigint"},
+				"day": {"type": "date"},
+				"tags": {"type": "set", "valueType": "text"},
+				"embedding": {"type": "vector", "dimension": 3, "service": {"provider": "nvidia", "modelName": "NV-Embed-QA"}},
+				"legacy": {"type": "UNSUPPORTED", "apiSupport": {"createTable": false, "insert": false, "read": false}}
+			},
+			"primaryKey": {"partitionBy": ["city"], "partitionSort": {"seq": 1, "day": -1}}
+		}},
+		{"name": "simple", "definition": {"columns": {"id": "text"}, "primaryKey": "id"}}
+	]}}`)
+	defer server.Close()
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+
+	tables, err := db.ListTables()
+	if err != nil {
+		t.Fatalf("ListTables failed: %v", err)
+	}
+	if (*received)[0] != `{"listTables":{"options":{"explain":true}}}` {
+		t.Errorf("Unexpected request: %s", (*received)[0])
+	}
+	if len(tables) != 2 {
+		t.Fatalf("Unexpected tables: %+v", tables)
+	}
+
+	readings := tables[0].Definition
+	if readings.Columns["tags"].ValueType != stragollum.ColumnTypeText || *readings.Columns["embedding"].Dimension != 3 ||
+		readings.Columns["embedding"].Service.Provider != "nvidia" || readings.Columns["legacy"].APISupport["read"] != false {
+		t.Errorf("Unexpected columns: %+v", readings.Columns)
+	}
+	// The clustering columns keep their order
+	sort := readings.PrimaryKey.PartitionSort
+	if len(sort) != 2 || sort[0] != (stragollum.ClusteringColumn{Column: "seq", Order: 1}) || sort[1] != (stragollum.ClusteringColumn{Column: "day", Order: -1}) {
+		t.Errorf("Unexpected partition sort: %+v", sort)
+	}
+
+	// Short forms of columns and primary keys
+	simple := tables[1].Definition
+	if simple.Columns["id"].Type != stragollum.ColumnTypeText || strings.Join(simple.PrimaryKey.PartitionBy, ",") != "id" {
+		t.Errorf("Unexpected definition: %+v", simple)
+	}
+
+	// Definitions read back can be used to create tables
+	encoded, err := json.Marshal(simple)
+	if err != nil || string(encoded) != `{"columns":{"id":{"type":"text"}},"primaryKey":{"partitionBy":["id"]}}` {
+		t.Errorf("Unexpected encoding: %s, %v", encoded, err)
+	}
+}
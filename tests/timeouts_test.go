@@ -0,0 +1,134 @@
+package stragollum_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"testing"
+	"time"
+)
+
+func newSlowServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data": {"document": {"_id": "a"}}}`)
+	}))
+}
+
+func TestTimeoutOptions_Inheritance(t *testing.T) {
+	timeouts := stragollum.TimeoutOptions{RequestTimeout: time.Second, OperationTimeout: 5 * time.Second}
+	client := stragollum.NewDataAPIClient(nil, nil).WithTimeoutOptions(timeouts)
+	if client.TimeoutOptions() != timeouts {
+		t.Errorf("client.TimeoutOptions() = %v; want %v", client.TimeoutOptions(), timeouts)
+	}
+
+	db := client.GetDatabase("https://api.example.com", nil, "ks1")
+	if db.TimeoutOptions() != timeouts || db.Commander().TimeoutOptions() != timeouts {
+		t.Errorf("Database did not inherit timeouts: %v", db.TimeoutOptions())
+	}
+
+	collection := db.GetCollection("coll", nil)
+	if collection.TimeoutOptions() != timeouts {
+		t.Errorf("Collection did not inherit timeouts: %v", collection.TimeoutOptions())
+	}
+
+	collTimeouts := stragollum.TimeoutOptions{RequestTimeout: 10 * time.Millisecond}
+	collection.WithTimeoutOptions(collTimeouts)
+	if collection.TimeoutOptions() != collTimeouts {
+		t.Errorf("collection.TimeoutOptions() = %v; want %v", collection.TimeoutOptions(), collTimeouts)
+	}
+	if db.TimeoutOptions() != timeouts {
+		t.Errorf("Database timeouts changed by collection override: %v", db.TimeoutOptions())
+	}
+}
+
+func TestTimeouts_RequestTimeout(t *testing.T) {
+	server := newSlowServer(500 * time.Millisecond)
+	defer server.Close()
+
+	commander := stragollum.NewDataAPICommander(server.URL, nil).
+		WithTimeoutOptions(stragollum.TimeoutOptions{RequestTimeout: 50 * time.Millisecond})
+
+	var response map[string]any
+	err := commander.Request(map[string]any{"findOne": map[string]any{}}, &response)
+	var tErr *stragollum.DataAPITimeoutError
+	if !errors.As(err, &tErr) {
+		t.Fatalf("Expected a *DataAPITimeoutError, got %T: %v", err, err)
+	}
+	if tErr.Kind != stragollum.TimeoutKindRequest || tErr.Timeout != 50*time.Millisecond {
+		t.Errorf("Unexpected timeout error: %+v", tErr)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error to match context.DeadlineExceeded")
+	}
+}
+
+func TestTimeouts_OperationTimeout(t *testing.T) {
+	server := newSlowServer(500 * time.Millisecond)
+	defer server.Close()
+
+	client := stragollum.NewDataAPIClient(nil, nil).
+		WithTimeoutOptions(stragollum.TimeoutOptions{OperationTimeout: 50 * time.Millisecond})
+	collection := client.GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+
+	_, err := collection.FindOne(map[string]any{})
+	var tErr *stragollum.DataAPITimeoutError
+	if !errors.As(err, &tErr) {
+		t.Fatalf("Expected a *DataAPITimeoutError, got %T: %v", err, err)
+	}
+	if tErr.Kind != stragollum.TimeoutKindOperation {
+		t.Errorf("Expected operation timeout, got %v", tErr.Kind)
+	}
+}
+
+func TestTimeouts_CallerContext(t *testing.T) {
+	server := newSlowServer(500 * time.Millisecond)
+	defer server.Close()
+
+	client := stragollum.NewDataAPIClient(nil, nil)
+	db := client.GetDatabase(server.URL, nil, "ks1")
+	collection := db.GetCollection("coll", nil)
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := collection.FindOneContext(ctx, map[string]any{})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("CallerDeadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := db.ListCollectionNamesContext(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		var tErr *stragollum.DataAPITimeoutError
+		if errors.As(err, &tErr) {
+			t.Errorf("Caller deadline should not be reported as a DataAPITimeoutError")
+		}
+	})
+
+	t.Run("NoTimeout", func(t *testing.T) {
+		fastServer := newSlowServer(0)
+		defer fastServer.Close()
+		doc, err := client.GetDatabase(fastServer.URL, nil, "ks1").GetCollection("coll", nil).
+			FindOneContext(context.Background(), map[string]any{})
+		if err != nil {
+			t.Fatalf("FindOneContext failed: %v", err)
+		}
+		if doc["_id"] != "a" {
+			t.Errorf("Unexpected document: %v", doc)
+		}
+	})
+}
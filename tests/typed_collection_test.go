@@ -0,0 +1,160 @@
+package stragollum_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+type audit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type article struct {
+	ID     string    `json:"id" dataapi:"id"`
+	Title  string    `json:"title"`
+	Tags   []string  `json:"tags,omitempty"`
+	Vector []float32 `json:"vector,omitempty" dataapi:"vector"`
+	Draft  bool      `json:"-"`
+	audit
+}
+
+func TestTypedCollection_InsertOne(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		expected := `{"insertOne":{"document":{"_id":"a1","title":"T","$vector":[0.5,0.25],"createdBy":"me"}}}`
+		if !bytes.Equal(body, []byte(expected)) {
+			t.Errorf("Unexpected payload:\n got %s\nwant %s", body, expected)
+		}
+		fmt.Fprint(w, `{"status": {"insertedIds": ["a1"]}}`)
+	}))
+	defer server.Close()
+
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+	collection := stragollum.GetTypedCollection[article](db, "articles", nil)
+	id, err := collection.InsertOne(article{
+		ID:     "a1",
+		Title:  "T",
+		Vector: []float32{0.5, 0.25},
+		Draft:  true,
+		audit:  audit{CreatedBy: "me"},
+	})
+	if err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	if id.String() != "a1" {
+		t.Errorf("Unexpected inserted ID: %v", id)
+	}
+}
+
+func TestTypedCollection_FindOne(t *testing.T) {
+	found := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !found {
+			fmt.Fprint(w, `{"data": {"document": null}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"document": {"_id": "a1", "title": "T", "tags": ["x"], "$vector": [0.5], "createdBy": "me"}}}`)
+	}))
+	defer server.Close()
+
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+	collection := stragollum.GetTypedCollection[article](db, "articles", nil)
+	doc, err := collection.FindOne(map[string]any{"_id": "a1"})
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if doc == nil || doc.ID != "a1" || doc.Title != "T" || len(doc.Tags) != 1 || len(doc.Vector) != 1 || doc.CreatedBy != "me" {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+
+	found = false
+	doc, err = collection.FindOne(map[string]any{"_id": "missing"})
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if doc != nil {
+		t.Errorf("Expected no document, got %+v", doc)
+	}
+}
+
+func TestTypedCollection_Find(t *testing.T) {
+	type numbered struct {
+		ID string `dataapi:"id"`
+		N  int    `json:"n"`
+	}
+	var received []map[string]any
+	server := newPagingServer(t, &received)
+	defer server.Close()
+
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+	collection := stragollum.GetTypedCollection[numbered](db, "numbers", nil)
+
+	cursor := collection.Find(nil, nil)
+	if !cursor.Next() {
+		t.Fatalf("Expected a document, got error %v", cursor.Err())
+	}
+	first, err := cursor.Current()
+	if err != nil || first.ID != "d1" || first.N != 1 {
+		t.Errorf("Unexpected current document: %+v, %v", first, err)
+	}
+	cursor.Close()
+
+	documents, err := collection.Find(nil, nil).All()
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if len(documents) != 6 || documents[5].ID != "d6" || documents[5].N != 6 {
+		t.Errorf("Unexpected documents: %+v", documents)
+	}
+}
+
+func TestTypedCollection_FindOneAndUpdate(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if name != "findOneAndUpdate" {
+			t.Errorf("Unexpected command %q", name)
+		}
+	}, `{"data": {"document": {"_id": "a1", "title": "new"}}, "status": {"matchedCount": 1, "modifiedCount": 1}}`)
+	defer server.Close()
+
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+	collection := stragollum.GetTypedCollection[article](db, "articles", nil)
+	doc, result, err := collection.FindOneAndUpdate(
+		map[string]any{"_id": "a1"},
+		stragollum.NewUpdate().Set("title", "new"),
+		&stragollum.FindOneAndUpdateOptions{ReturnDocument: stragollum.ReturnDocumentAfter},
+	)
+	if err != nil {
+		t.Fatalf("FindOneAndUpdate failed: %v", err)
+	}
+	if doc == nil || doc.Title != "new" || result.ModifiedCount != 1 {
+		t.Errorf("Unexpected outcome: %+v, %+v", doc, result)
+	}
+}
+
+func TestTypedCollection_StructFilter(t *testing.T) {
+	type byID struct {
+		ID string `dataapi:"id"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if filter := payload["findOne"]["filter"].(map[string]any); filter["_id"] != "a1" || len(filter) != 1 {
+			t.Errorf("Unexpected filter: %v", filter)
+		}
+		fmt.Fprint(w, `{"data": {"document": null}}`)
+	}))
+	defer server.Close()
+
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+	if _, err := db.GetCollection("articles", nil).FindOne(byID{ID: "a1"}); err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+}
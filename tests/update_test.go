@@ -0,0 +1,160 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+func TestUpdate_JSON(t *testing.T) {
+	update := stragollum.NewUpdate().
+		Set("a", 1).
+		Set("b.c", "x").
+		Unset("old").
+		Inc("n", 2).
+		PushEach("tags", []any{"t1", "t2"}, 0).
+		Push("log", "entry").
+		AddToSet("set", 5).
+		AddToSetEach("set2", []any{1, 2}).
+		PopFirst("queue").
+		PopLast("stack").
+		Rename("from", "to").
+		Min("low", 3).
+		Max("high", 9).
+		Mul("price", 1.5).
+		CurrentDate("updatedAt").
+		SetOnInsert("createdBy", "me")
+
+	actual, err := json.Marshal(update)
+	if err != nil {
+		t.Fatalf("Failed to marshal Update: %v", err)
+	}
+
+	expectedJSON := `{
+		"$set": {"a": 1, "b.c": "x"},
+		"$unset": {"old": ""},
+		"$inc": {"n": 2},
+		"$push": {"tags": {"$each": ["t1", "t2"], "$position": 0}, "log": "entry"},
+		"$addToSet": {"set": 5, "set2": {"$each": [1, 2]}},
+		"$pop": {"queue": -1, "stack": 1},
+		"$rename": {"from": "to"},
+		"$min": {"low": 3},
+		"$max": {"high": 9},
+		"$mul": {"price": 1.5},
+		"$currentDate": {"updatedAt": true},
+		"$setOnInsert": {"createdBy": "me"}
+	}`
+
+	var expected, got map[string]any
+	if err := json.Unmarshal([]byte(expectedJSON), &expected); err != nil {
+		t.Fatalf("Failed to unmarshal expected JSON: %v", err)
+	}
+	if err := json.Unmarshal(actual, &got); err != nil {
+		t.Fatalf("Failed to unmarshal actual JSON: %v", err)
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("Update JSON does not match expected.\nExpected: %v\nGot: %v", expected, got)
+	}
+}
+
+func TestCollection_UpdateOne(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		updateOne, ok := payload["updateOne"].(map[string]any)
+		if !ok {
+			t.Fatalf("Payload missing updateOne: %v", payload)
+		}
+		if updateOne["filter"].(map[string]any)["name"] != "x" {
+			t.Errorf("Unexpected filter: %v", updateOne["filter"])
+		}
+		if updateOne["update"].(map[string]any)["$set"].(map[string]any)["v"] != float64(1) {
+			t.Errorf("Unexpected update: %v", updateOne["update"])
+		}
+		if updateOne["sort"].(map[string]any)["rank"] != float64(-1) {
+			t.Errorf("Unexpected sort: %v", updateOne["sort"])
+		}
+		if updateOne["options"].(map[string]any)["upsert"] != true {
+			t.Errorf("Unexpected options: %v", updateOne["options"])
+		}
+		fmt.Fprint(w, `{"status": {"matchedCount": 0, "modifiedCount": 0, "upsertedId": "new-id"}}`)
+	}))
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	result, err := collection.UpdateOne(
+		map[string]any{"name": "x"},
+		stragollum.NewUpdate().Set("v", 1),
+		&stragollum.UpdateOneOptions{Upsert: true, Sort: map[string]any{"rank": -1}},
+	)
+	if err != nil {
+		t.Fatalf("UpdateOne failed: %v", err)
+	}
+	if result.MatchedCount != 0 || result.UpsertedCount != 1 || result.UpsertedID != "new-id" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestCollection_UpdateMany(t *testing.T) {
+	var pageStates []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		updateMany := payload["updateMany"].(map[string]any)
+		var pageState any
+		if options, ok := updateMany["options"].(map[string]any); ok {
+			pageState = options["pageState"]
+		}
+		pageStates = append(pageStates, pageState)
+		switch pageState {
+		case nil:
+			fmt.Fprint(w, `{"status": {"matchedCount": 20, "modifiedCount": 20, "moreData": true, "nextPageState": "p2"}}`)
+		case "p2":
+			fmt.Fprint(w, `{"status": {"matchedCount": 20, "modifiedCount": 19, "moreData": true, "nextPageState": "p3"}}`)
+		default:
+			fmt.Fprint(w, `{"status": {"matchedCount": 5, "modifiedCount": 5}}`)
+		}
+	}))
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	result, err := collection.UpdateMany(map[string]any{"group": "g"}, stragollum.NewUpdate().Inc("n", 1), nil)
+	if err != nil {
+		t.Fatalf("UpdateMany failed: %v", err)
+	}
+	if result.MatchedCount != 45 || result.ModifiedCount != 44 || result.UpsertedCount != 0 || result.UpsertedID != nil {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if !reflect.DeepEqual(pageStates, []any{nil, "p2", "p3"}) {
+		t.Errorf("Unexpected page states: %v", pageStates)
+	}
+
+	// Upsert on an empty match
+	upsertServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if payload["updateMany"].(map[string]any)["options"].(map[string]any)["upsert"] != true {
+			t.Errorf("Expected upsert option: %v", payload)
+		}
+		fmt.Fprint(w, `{"status": {"matchedCount": 0, "modifiedCount": 0, "upsertedId": "u1"}}`)
+	}))
+	defer upsertServer.Close()
+	collection = stragollum.NewDataAPIClient(nil, nil).GetDatabase(upsertServer.URL, nil, "ks1").GetCollection("coll", nil)
+	result, err = collection.UpdateMany(map[string]any{}, stragollum.NewUpdate().Set("a", 1), &stragollum.UpdateManyOptions{Upsert: true})
+	if err != nil {
+		t.Fatalf("UpdateMany failed: %v", err)
+	}
+	if result.UpsertedCount != 1 || result.UpsertedID != "u1" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
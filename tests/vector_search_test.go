@@ -0,0 +1,85 @@
+package stragollum_test
+
+import (
+	"reflect"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+func TestCollection_FindByVector(t *testing.T) {
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if name != "find" {
+			t.Errorf("Unexpected command %q", name)
+		}
+		if !reflect.DeepEqual(command["sort"].(map[string]any)["$vector"], []any{0.5, 0.5}) {
+			t.Errorf("Unexpected sort: %v", command["sort"])
+		}
+		if command["filter"].(map[string]any)["lang"] != "en" {
+			t.Errorf("Unexpected filter: %v", command["filter"])
+		}
+		options := command["options"].(map[string]any)
+		if options["limit"] != float64(2) || options["includeSimilarity"] != true || options["includeSortVector"] != true {
+			t.Errorf("Unexpected options: %v", options)
+		}
+	}, `{"data": {"documents": [{"_id": "d1", "$similarity": 0.9}, {"_id": "d2", "$similarity": 0.7}], "nextPageState": null}, "status": {"sortVector": [0.5, 0.5]}}`)
+	defer server.Close()
+
+	collection := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1").GetCollection("coll", nil)
+	result, err := collection.FindByVector([]float32{0.5, 0.5}, 2, map[string]any{"lang": "en"}, &stragollum.VectorSearchOptions{IncludeSortVector: true})
+	if err != nil {
+		t.Fatalf("FindByVector failed: %v", err)
+	}
+	if len(result.Documents) != 2 || result.Documents[0].Document["_id"] != "d1" || result.Documents[0].Similarity != 0.9 || result.Documents[1].Similarity != 0.7 {
+		t.Errorf("Unexpected documents: %+v", result.Documents)
+	}
+	if !reflect.DeepEqual(result.SortVector, []float32{0.5, 0.5}) {
+		t.Errorf("Unexpected sort vector: %v", result.SortVector)
+	}
+
+	if _, err := collection.FindByVector([]float32{0.5, 0.5}, 0, nil, nil); err == nil {
+		t.Errorf("Expected an error for k=0")
+	}
+}
+
+func TestTypedCollection_FindByVectorize(t *testing.T) {
+	type passage struct {
+		ID   string `dataapi:"id"`
+		Text string `json:"text"`
+	}
+	server := newCommandServer(t, func(name string, command map[string]any) {
+		if command["sort"].(map[string]any)["$vectorize"] != "query text" {
+			t.Errorf("Unexpected sort: %v", command["sort"])
+		}
+		if _, ok := command["options"].(map[string]any)["includeSortVector"]; ok {
+			t.Errorf("includeSortVector not expected: %v", command["options"])
+		}
+	}, `{"data": {"documents": [{"_id": "p1", "text": "hello", "$similarity": 0.8}], "nextPageState": null}}`)
+	defer server.Close()
+
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+	collection := stragollum.GetTypedCollection[passage](db, "passages", nil)
+	result, err := collection.FindByVectorize("query text", 5, nil, nil)
+	if err != nil {
+		t.Fatalf("FindByVectorize failed: %v", err)
+	}
+	if len(result.Documents) != 1 || result.Documents[0].Document.ID != "p1" || result.Documents[0].Document.Text != "hello" || result.Documents[0].Similarity != 0.8 {
+		t.Errorf("Unexpected documents: %+v", result.Documents)
+	}
+	if result.SortVector != nil {
+		t.Errorf("No sort vector expected, got %v", result.SortVector)
+	}
+}
+
+func TestVectorDocumentHelpers(t *testing.T) {
+	document := stragollum.SetVector(nil, []float32{1, 2})
+	if !reflect.DeepEqual(document[stragollum.VectorField], stragollum.DataAPIVector{1, 2}) {
+		t.Errorf("Unexpected document: %v", document)
+	}
+	document = stragollum.SetVectorize(map[string]interface{}{"a": 1}, "some text")
+	if document["$vectorize"] != "some text" || document["a"] != 1 {
+		t.Errorf("Unexpected document: %v", document)
+	}
+	if sort := stragollum.VectorizeSort("q"); sort["$vectorize"] != "q" || len(sort) != 1 {
+		t.Errorf("Unexpected sort: %v", sort)
+	}
+}
@@ -0,0 +1,109 @@
+package stragollum_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"stragollum/pkg/stragollum"
+	"testing"
+)
+
+func TestDataAPIVector_JSON(t *testing.T) {
+	vector := stragollum.DataAPIVector{0.5, -1, 2}
+	encoded, err := json.Marshal(vector)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(encoded) != `[0.5,-1,2]` {
+		t.Errorf("Unexpected encoding: %s", encoded)
+	}
+
+	for _, representation := range []string{`{"$binary": "PwAAAL+AAABAAAAA"}`, `[0.5, -1, 2]`} {
+		var decoded stragollum.DataAPIVector
+		if err := json.Unmarshal([]byte(representation), &decoded); err != nil {
+			t.Fatalf("Unmarshal of %s failed: %v", representation, err)
+		}
+		if !reflect.DeepEqual(decoded, vector) {
+			t.Errorf("Unexpected decoding of %s: %v", representation, decoded)
+		}
+	}
+
+	var decoded stragollum.DataAPIVector
+	if err := json.Unmarshal([]byte(`{"$binary": "PwAA"}`), &decoded); err == nil {
+		t.Errorf("Expected an error for a truncated binary vector")
+	}
+}
+
+func TestVectorEncoding(t *testing.T) {
+	type item struct {
+		ID        string                    `dataapi:"id"`
+		Embedding stragollum.DataAPIVector  `dataapi:"vector"`
+		Other     *stragollum.DataAPIVector `json:"other,omitempty"`
+	}
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.Write([]byte(`{"status": {"insertedIds": ["i1"]}}`))
+	}))
+	defer server.Close()
+
+	other := stragollum.DataAPIVector{1}
+	document := item{ID: "i1", Embedding: stragollum.DataAPIVector{0.5, -1, 2}, Other: &other}
+
+	client := stragollum.NewDataAPIClient(nil, nil)
+	if client.VectorEncoding() != stragollum.VectorEncodingArray {
+		t.Errorf("Expected array encoding by default, got %v", client.VectorEncoding())
+	}
+	collection := client.GetDatabase(server.URL, nil, "ks1").GetCollection("items", nil)
+	if _, err := collection.InsertOne(document); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	expected := `{"insertOne":{"document":{"_id":"i1","$vector":[0.5,-1,2],"other":[1]}}}`
+	if received != expected {
+		t.Errorf("Unexpected payload:\n got %s\nwant %s", received, expected)
+	}
+
+	// The setting is inherited from the client, and can be overridden by the collection
+	client.WithVectorEncoding(stragollum.VectorEncodingBinary)
+	db := client.GetDatabase(server.URL, nil, "ks1")
+	if _, err := db.GetCollection("items", nil).InsertOne(document); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	expected = `{"insertOne":{"document":{"_id":"i1","$vector":{"$binary":"PwAAAL+AAABAAAAA"},"other":{"$binary":"P4AAAA=="}}}}`
+	if received != expected {
+		t.Errorf("Unexpected payload:\n got %s\nwant %s", received, expected)
+	}
+
+	collection = db.GetCollection("items", nil).WithVectorEncoding(stragollum.VectorEncodingArray)
+	if _, err := collection.InsertOne(stragollum.SetVector(map[string]interface{}{"_id": "i2"}, []float32{1})); err != nil {
+		t.Fatalf("InsertOne failed: %v", err)
+	}
+	expected = `{"insertOne":{"document":{"$vector":[1],"_id":"i2"}}}`
+	if received != expected {
+		t.Errorf("Unexpected payload:\n got %s\nwant %s", received, expected)
+	}
+}
+
+func TestVectorDecoding(t *testing.T) {
+	type item struct {
+		ID        string                   `dataapi:"id"`
+		Embedding stragollum.DataAPIVector `dataapi:"vector"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"document": {"_id": "i1", "$vector": {"$binary": "PwAAAL+AAABAAAAA"}}}}`))
+	}))
+	defer server.Close()
+
+	db := stragollum.NewDataAPIClient(nil, nil).GetDatabase(server.URL, nil, "ks1")
+	doc, err := stragollum.GetTypedCollection[item](db, "items", nil).FindOne(nil)
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if doc == nil || !reflect.DeepEqual(doc.Embedding, stragollum.DataAPIVector{0.5, -1, 2}) {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+}